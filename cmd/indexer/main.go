@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
+	"go-eth-backend/internal/pkg/indexer"
+)
+
+// 区块索引器
+// 将区块、交易、收据和日志写入config.yaml中配置的PostgreSQL，
+// 进度保存在检查点表中，重启后从上次的位置继续
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "配置文件路径")
+	network := flag.String("network", "sepolia", "要索引的网络名称")
+	start := flag.Uint64("start", 0, "没有检查点时开始索引的区块号")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("❌ 配置加载失败: %v", err)
+	}
+
+	dbConfig := cfg.GetDatabaseConfig()
+	if !dbConfig.Enabled {
+		log.Fatal("❌ 数据库未启用: 请在配置文件中设置 database.enabled")
+	}
+
+	client, err := eth.NewClientForNetwork(cfg, *network)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer client.Close()
+
+	networkConfig := client.NetworkConfig()
+	pollInterval, err := networkConfig.GetBlockTime()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 按链ID区分网络，多个网络可以索引到同一个数据库
+	store, err := indexer.NewPostgresStorage(ctx, dbConfig.DSN(), networkConfig.ChainID)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer store.Close()
+
+	ix := indexer.New(client, store, indexer.Options{
+		StartBlock:    *start,
+		Confirmations: networkConfig.Confirmations,
+		PollInterval:  pollInterval,
+	})
+
+	log.Printf("🚀 开始索引网络 %s", *network)
+	if err := ix.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("❌ 索引器异常退出: %v", err)
+	}
+	log.Println("👋 索引器已停止")
+}
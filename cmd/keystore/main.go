@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+// keystore导入工具
+// 将十六进制私钥加密为Web3 Secret Storage格式的keystore文件，
+// 以替代在config.yaml中保存明文私钥
+
+func main() {
+	out := flag.String("out", "keystore/dev.json", "keystore输出文件路径")
+	passphraseEnv := flag.String("passphrase-env", "ETH_KEYSTORE_PASSPHRASE", "读取口令的环境变量")
+	flag.Parse()
+
+	passphrase, ok := os.LookupEnv(*passphraseEnv)
+	if !ok || passphrase == "" {
+		log.Fatalf("❌ 请先设置环境变量 %s 作为keystore口令", *passphraseEnv)
+	}
+
+	// 从标准输入读取私钥，避免私钥出现在命令行历史中
+	fmt.Print("🔐 请输入十六进制私钥: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatalf("❌ 读取私钥失败: %v", err)
+	}
+
+	key, keyJSON, err := encryptKey(line, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0o700); err != nil {
+		log.Fatalf("❌ 创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(*out, keyJSON, 0o600); err != nil {
+		log.Fatalf("❌ 写入keystore文件失败: %v", err)
+	}
+
+	fmt.Printf("✅ 已生成keystore文件: %s\n", *out)
+	fmt.Printf("📧 账户地址: %s\n", key.Address.Hex())
+}
+
+// encryptKey 将十六进制私钥（可带0x前缀和首尾空白）以口令加密为keystore JSON
+func encryptKey(hexKey, passphrase string, scryptN, scryptP int) (*keystore.Key, []byte, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("私钥解析失败: %w", err)
+	}
+
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	keyJSON, err := keystore.EncryptKey(key, passphrase, scryptN, scryptP)
+	if err != nil {
+		return nil, nil, fmt.Errorf("加密私钥失败: %w", err)
+	}
+
+	return key, keyJSON, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+func TestEncryptKeyRoundTrip(t *testing.T) {
+	// 与标准输入读到的一样带0x前缀和换行
+	const hexKey = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318\n"
+
+	key, keyJSON, err := encryptKey(hexKey, "s3cret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("加密私钥失败: %v", err)
+	}
+
+	signer, err := eth.NewKeystoreSigner(keyJSON, "s3cret")
+	if err != nil {
+		t.Fatalf("口令正确时解密失败: %v", err)
+	}
+	if signer.Address() != key.Address || key.Address.Hex() != "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+		t.Errorf("地址 = %s, want 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", signer.Address().Hex())
+	}
+
+	if _, err := eth.NewKeystoreSigner(keyJSON, "wrong"); err == nil {
+		t.Error("口令错误时应解密失败")
+	}
+	if _, _, err := encryptKey("0x1234", "s3cret", keystore.LightScryptN, keystore.LightScryptP); err == nil {
+		t.Error("无效私钥应返回错误")
+	}
+}
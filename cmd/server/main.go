@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go-eth-backend/internal/pkg/api"
+	"go-eth-backend/internal/pkg/cache"
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// REST API服务器
+// 将区块、交易、账户和gas价格查询以JSON形式提供给前端
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "配置文件路径")
+	network := flag.String("network", "sepolia", "要连接的网络名称")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("❌ 配置加载失败: %v", err)
+	}
+
+	client, err := eth.NewClientForNetwork(cfg, *network)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer client.Close()
+
+	var reader eth.Reader = client
+	if cacheConfig := cfg.GetCacheConfig(); cacheConfig.Enabled {
+		backend, err := cache.NewBackend(cacheConfig)
+		if err != nil {
+			log.Fatalf("❌ 创建缓存失败: %v", err)
+		}
+		defer backend.Close()
+
+		reader = cache.New(client, backend, cache.Options{
+			Network: *network,
+			TTL:     cacheConfig.GetTTL(),
+		})
+		log.Printf("🗄️  已启用%s缓存", cacheConfig.Backend)
+	}
+
+	server, err := api.NewServer(reader, cfg.GetServerConfig())
+	if err != nil {
+		log.Fatalf("❌ 创建服务器失败: %v", err)
+	}
+
+	// 配置了计数器合约地址时提供 /counter 接口，只有开启了 counter_writes 才加载签名账户，否则只读
+	if address := client.NetworkConfig().CounterAddress; address != "" {
+		var signer eth.Signer
+		if cfg.GetServerConfig().CounterWrites && cfg.GetAccountsConfig().HasSigner() {
+			signer, err = eth.NewSignerFromConfig(cfg.GetAccountsConfig())
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+		}
+
+		counter, err := eth.LoadCounterService(client, common.HexToAddress(address), cfg.GetServerConfig().CounterABIFile, signer)
+		if err != nil {
+			log.Fatalf("❌ 创建计数器合约服务失败: %v", err)
+		}
+		server.SetCounter(counter)
+		log.Printf("🔢 计数器合约: %s", address)
+		if cfg.GetServerConfig().CounterWrites {
+			log.Printf("🔐 已开启计数器写接口，请求需携带访问令牌")
+		}
+	}
+
+	go func() {
+		log.Printf("🚀 REST API服务器已启动: http://%s", server.Addr())
+		if err := server.ListenAndServe(); err != nil {
+			log.Fatalf("❌ 服务器异常退出: %v", err)
+		}
+	}()
+
+	// 推送服务直接使用节点订阅，不经过缓存
+	var streamServer *api.StreamServer
+	if serverConfig := cfg.GetServerConfig(); serverConfig.StreamPort > 0 {
+		streamServer, err = api.NewStreamServer(client, serverConfig)
+		if err != nil {
+			log.Fatalf("❌ 创建推送服务器失败: %v", err)
+		}
+		for _, path := range serverConfig.StreamABIFiles {
+			contractABI, err := eth.LoadABI(path)
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			streamServer.RegisterABI(contractABI)
+		}
+
+		go func() {
+			log.Printf("📡 推送服务已启动: http://%s/stream/blocks", streamServer.Addr())
+			if err := streamServer.ListenAndServe(); err != nil {
+				log.Fatalf("❌ 推送服务异常退出: %v", err)
+			}
+		}()
+	}
+
+	// 等待退出信号并优雅关闭
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  服务器关闭失败: %v", err)
+	}
+	if streamServer != nil {
+		if err := streamServer.Shutdown(ctx); err != nil {
+			log.Printf("⚠️  推送服务关闭失败: %v", err)
+		}
+	}
+	log.Println("👋 服务器已关闭")
+}
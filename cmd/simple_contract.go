@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -5,17 +7,23 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"os"
+	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	counter "go-eth-backend/pkg/eth/counter" // 导入生成的绑定代码
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
 )
 
 // 智能合约交互示例程序
 // 演示如何部署和调用SimpleCounter合约
 
+// SimpleCounter合约的编译产物（ABI与字节码需出自同一次编译）
+const (
+	abiPath = "contracts/build/contracts_Counter_sol_SimpleCounter.abi"
+	binPath = "contracts/build/contracts_Counter_sol_SimpleCounter.bin"
+)
+
 func main() {
 	fmt.Println("=== 智能合约交互示例程序 ===")
 	fmt.Println("任务目标: 部署SimpleCounter合约并进行交互")
@@ -26,7 +34,7 @@ func main() {
 	rpcURL := fmt.Sprintf("https://sepolia.infura.io/v3/%s", infuraAPIKey)
 
 	fmt.Println("🔗 正在连接到Sepolia测试网络...")
-	client, err := ethclient.Dial(rpcURL)
+	client, err := eth.NewClient(rpcURL)
 	if err != nil {
 		log.Fatalf("❌ 连接失败: %v", err)
 	}
@@ -36,31 +44,24 @@ func main() {
 	fmt.Println()
 
 	// Step 2: 配置账户信息
-	privateKeyHex := "4323d9e4f879855a70a3c19b732dde4d1bdb0829b0c30be408ad4b8e24e45e60" // 测试网络私钥
-	
-	if privateKeyHex == "YOUR_PRIVATE_KEY_HERE" {
-		showTutorial(client)
-		return
-	}
-
-	// 解析私钥
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	// 从配置文件加载签名账户，不再在代码中硬编码私钥
+	cfg, err := config.LoadConfig("config.yaml")
 	if err != nil {
-		log.Fatalf("❌ 私钥解析失败: %v", err)
+		log.Fatalf("❌ 配置加载失败: %v", err)
 	}
 
-	// 获取账户地址
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("❌ 无法获取公钥")
+	signer, err := eth.NewSignerFromConfig(cfg.GetAccountsConfig())
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		showTutorial(client)
+		return
 	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	fromAddress := signer.Address()
 
 	fmt.Printf("📧 账户地址: %s\n", fromAddress.Hex())
 
 	// Step 3: 检查账户余额
-	balance, err := client.BalanceAt(context.Background(), fromAddress, nil)
+	balance, err := client.GetBalance(context.Background(), fromAddress.Hex())
 	if err != nil {
 		log.Fatalf("❌ 查询余额失败: %v", err)
 	}
@@ -75,67 +76,59 @@ func main() {
 	}
 	fmt.Println()
 
-	// Step 4: 准备交易授权
-	fmt.Println("🔧 准备交易授权...")
-	
-	chainID, err := client.ChainID(context.Background())
-	if err != nil {
-		log.Fatalf("❌ 获取链ID失败: %v", err)
-	}
+	// Step 4: 加载合约ABI和字节码
+	// 直接使用编译产物，不需要abigen生成的绑定代码
+	fmt.Println("🔧 加载合约ABI和字节码...")
 
-	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	counterABI, err := eth.LoadABI(abiPath)
 	if err != nil {
-		log.Fatalf("❌ 创建交易授权失败: %v", err)
+		log.Fatalf("❌ %v", err)
 	}
-
-	// 设置Gas价格
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	bytecode, err := os.ReadFile(binPath)
 	if err != nil {
-		log.Fatalf("❌ 获取Gas价格失败: %v", err)
+		log.Fatalf("❌ 读取合约字节码失败: %v", err)
 	}
-	auth.GasPrice = gasPrice
-
-	fmt.Printf("⛽ Gas价格: %s wei\n", auth.GasPrice.String())
 	fmt.Println()
 
 	// Step 5: 部署合约
+	// SimpleCounter在构造函数中把 msg.sender 记为owner，必须由签名账户直接发送创建交易；
+	// 通过CREATE2工厂（eth.Deployer）部署时owner会是工厂地址，reset等仅owner可调用的方法将不可用
 	fmt.Println("🚀 部署SimpleCounter合约...")
-	
-	contractAddress, tx, contractInstance, err := counter.DeployCounter(auth, client)
+
+	// gas、nonce和费用由 eth.Client 按网络配置自动补齐
+	contract, deployTx, err := eth.DeployContract(context.Background(), client, signer, counterABI,
+		common.FromHex(strings.TrimSpace(string(bytecode))), eth.TxRequest{})
 	if err != nil {
 		log.Fatalf("❌ 合约部署失败: %v", err)
 	}
+	fmt.Printf("📋 交易哈希: %s\n", deployTx.Hash)
 
-	fmt.Printf("✅ 合约部署交易已发送!\n")
-	fmt.Printf("📋 交易哈希: %s\n", tx.Hash().Hex())
-	fmt.Printf("📋 合约地址: %s\n", contractAddress.Hex())
-	fmt.Println()
-
-	// Step 6: 等待合约部署确认
-	fmt.Println("⏳ 等待合约部署确认...")
-	
-	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	fmt.Println("⏳ 等待部署交易确认...")
+	deployReceipt, err := client.WaitForTransactionReceipt(context.Background(), deployTx.Hash)
 	if err != nil {
-		log.Fatalf("❌ 等待交易确认失败: %v", err)
+		log.Fatalf("❌ 等待部署交易确认失败: %v", err)
+	}
+	if deployReceipt.Status != 1 {
+		fmt.Printf("📋 失败原因: %s\n", client.FailureReason(context.Background(), deployTx.Hash))
+		log.Fatal("❌ 部署交易执行失败!")
 	}
 
-	if receipt.Status == 1 {
-		fmt.Printf("✅ 合约部署成功!\n")
-		fmt.Printf("📋 区块号: %d\n", receipt.BlockNumber.Uint64())
-		fmt.Printf("📋 Gas使用量: %d\n", receipt.GasUsed)
-	} else {
-		log.Fatal("❌ 合约部署失败!")
+	fmt.Printf("✅ 合约部署成功!\n")
+	fmt.Printf("📋 合约地址: %s\n", contract.Address().Hex())
+	fmt.Printf("📋 区块号: %d\n", deployReceipt.BlockNumber.Uint64())
+	if owner, err := contract.Call(context.Background(), "getOwner"); err == nil {
+		fmt.Printf("📋 合约owner: %s\n", owner[0].(common.Address).Hex())
 	}
 	fmt.Println()
 
-	// Step 7: 与已部署的合约交互
+	// Step 6: 与已部署的合约交互
 	fmt.Println("🤖 与合约交互...")
-	
+
 	// 方法1: 读取合约状态（不需要Gas）
 	fmt.Println("1. 读取合约状态:")
-	
+
 	// 获取当前计数值
-	currentCount, err := contractInstance.GetCount(nil)
+	currentCount, err := getCount(contract)
 	if err != nil {
 		log.Fatalf("❌ 读取计数值失败: %v", err)
 	}
@@ -143,31 +136,44 @@ func main() {
 
 	// 方法2: 调用合约方法（需要Gas）
 	fmt.Println("\n2. 调用合约方法:")
-	
-	// 调用increment方法增加计数器
-	fmt.Println("  调用increment()方法...")
-	
-	incrementTx, err := contractInstance.Increment(auth)
+
+	// 调用increment方法增加计数器，参数可以直接使用字符串，按ABI转换为uint256
+	fmt.Println("  调用increment(1)方法...")
+
+	incrementTx, err := contract.Transact(context.Background(), signer, "increment", "1")
 	if err != nil {
 		log.Fatalf("❌ 调用increment方法失败: %v", err)
 	}
 
-	fmt.Printf("  ✅ Increment交易已发送: %s\n", incrementTx.Hash().Hex())
+	fmt.Printf("  ✅ Increment交易已发送: %s\n", incrementTx.Hash)
 
 	// 等待交易确认
 	fmt.Println("  等待交易确认...")
-	
-	_, err = bind.WaitMined(context.Background(), client, incrementTx)
+
+	incrementReceipt, err := client.WaitForTransactionReceipt(context.Background(), incrementTx.Hash)
 	if err != nil {
 		log.Fatalf("❌ 等待交易确认失败: %v", err)
 	}
+	if incrementReceipt.Status != 1 {
+		fmt.Printf("📋 失败原因: %s\n", client.FailureReason(context.Background(), incrementTx.Hash))
+		log.Fatal("❌ Increment交易执行失败!")
+	}
 
 	fmt.Println("  ✅ 交易确认成功!")
 
+	// 解码交易产生的事件
+	events, err := contract.DecodeReceipt(incrementReceipt)
+	if err != nil {
+		fmt.Printf("  ⚠️ 解码事件失败: %v\n", err)
+	}
+	for _, event := range events {
+		fmt.Printf("  📣 %s newCount=%v by=%v\n", event.Name, event.Args["newCount"], event.Args["by"])
+	}
+
 	// 再次读取计数值验证变化
 	fmt.Println("\n3. 验证计数值变化:")
-	
-	updatedCount, err := contractInstance.GetCount(nil)
+
+	updatedCount, err := getCount(contract)
 	if err != nil {
 		log.Fatalf("❌ 读取计数值失败: %v", err)
 	}
@@ -180,16 +186,20 @@ func main() {
 	}
 	fmt.Println()
 
-	// Step 8: 与现有合约交互（如果不想部署新合约）
+	// 查询部署以来的合约事件
+	queryContractEvents(contract, deployReceipt.BlockNumber.Uint64())
+	fmt.Println()
+
+	// Step 7: 与现有合约交互（如果不想部署新合约）
 	fmt.Println("💡 与现有合约交互示例:")
-	
+
 	// 示例：连接到一个已存在的合约
 	existingContractAddress := common.HexToAddress("0x...") // 替换为实际的合约地址
-	existingContract, err := counter.NewCounter(existingContractAddress, client)
+	existingContract, err := eth.LoadContract(client, existingContractAddress, abiPath)
 	if err != nil {
 		fmt.Println("  ⚠️ 无法连接示例合约（需要实际合约地址）")
 	} else {
-		existingCount, err := existingContract.GetCount(nil)
+		existingCount, err := getCount(existingContract)
 		if err != nil {
 			fmt.Println("  ⚠️ 无法读取示例合约状态")
 		} else {
@@ -200,7 +210,7 @@ func main() {
 	fmt.Println()
 	fmt.Println("=== 智能合约交互示例完成 ===")
 	fmt.Println("📝 总结:")
-	fmt.Println("1. 成功部署了SimpleCounter合约")
+	fmt.Println("1. 部署了SimpleCounter合约，owner为签名账户")
 	fmt.Println("2. 调用了合约的increment方法")
 	fmt.Println("3. 验证了合约状态的改变")
 	fmt.Println()
@@ -210,34 +220,43 @@ func main() {
 	fmt.Println("- 可以部署更复杂的合约")
 }
 
+// getCount 调用合约的getCount方法
+func getCount(contract *eth.Contract) (*big.Int, error) {
+	results, err := contract.Call(context.Background(), "getCount")
+	if err != nil {
+		return nil, err
+	}
+	return results[0].(*big.Int), nil
+}
+
 // 显示教程信息
-func showTutorial(client *ethclient.Client) {
+func showTutorial(client *eth.Client) {
 	fmt.Println("📚 使用教程:")
 	fmt.Println("1. 准备工作:")
 	fmt.Println("   - 获取Sepolia测试ETH: https://sepoliafaucet.com/")
-	fmt.Println("   - 配置私钥到 simple_contract.go 文件")
+	fmt.Println("   - 在 config.yaml 中配置 keystore 及口令（或本地开发用的 insecure_dev_key）")
 	fmt.Println()
-	
-	fmt.Println("2. 生成Go绑定代码:")
-	fmt.Println("   $ chmod +x scripts/compile_and_generate.sh")
-	fmt.Println("   $ ./scripts/compile_and_generate.sh")
+
+	fmt.Println("2. 准备合约ABI和字节码（无需abigen生成绑定代码）:")
+	fmt.Printf("   %s\n", abiPath)
+	fmt.Printf("   %s\n", binPath)
 	fmt.Println()
-	
+
 	fmt.Println("3. 运行程序:")
 	fmt.Println("   $ go run cmd/simple_contract.go")
 	fmt.Println()
-	
+
 	fmt.Println("4. 智能合约详情:")
 	fmt.Println("   合约名称: SimpleCounter")
 	fmt.Println("   合约方法:")
-	fmt.Println("     - increment(): 增加计数器")
+	fmt.Println("     - increment(uint256): 增加计数器")
 	fmt.Println("     - getCount(): 获取当前计数值")
 	fmt.Println("   合约事件: CountIncremented")
 	fmt.Println()
-	
+
 	// 检查网络连接
 	fmt.Println("🌐 网络连接状态:")
-	blockNumber, err := client.BlockNumber(context.Background())
+	blockNumber, err := client.GetLatestBlockNumber(context.Background())
 	if err != nil {
 		fmt.Println("❌ 网络连接失败")
 	} else {
@@ -246,10 +265,23 @@ func showTutorial(client *ethclient.Client) {
 }
 
 // 查询合约事件（辅助函数）
-func queryContractEvents(contractInstance *counter.Counter) {
+// 从部署区块开始查询计数器事件，节点限制查询范围时会自动分段
+func queryContractEvents(contract *eth.Contract, fromBlock uint64) {
 	fmt.Println("📊 查询合约事件...")
-	
-	// 这里可以添加事件查询逻辑
-	// 实际使用时需要实现事件过滤和监听
-	fmt.Println("  事件查询功能待实现...")
-}
\ No newline at end of file
+
+	events, err := contract.Events(context.Background(), fromBlock, nil,
+		"CountIncremented", "CountDecremented", "CountReset")
+	if err != nil {
+		fmt.Printf("⚠️  查询事件失败: %v\n", err)
+		return
+	}
+
+	if len(events) == 0 {
+		fmt.Println("  暂无事件")
+		return
+	}
+	for _, event := range events {
+		fmt.Printf("  区块 %d: %s newCount=%v by=%v\n",
+			event.BlockNumber, event.Name, event.Args["newCount"], event.Args["by"])
+	}
+}
@@ -1,8 +1,9 @@
+//go:build ignore
+
 package main
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
 	"log"
 	"math/big"
@@ -10,9 +11,9 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
 )
 
 // 交易发送示例程序
@@ -36,7 +37,7 @@ func main() {
 	
 	// 从配置文件获取网络配置
 	sepoliaConfig := config.GetSepoliaConfig()
-	rpcURL := sepoliaConfig.RPCURL
+	rpcURL := sepoliaConfig.RPCURL()
 
 	fmt.Println("🔗 正在连接到Sepolia测试网络...")
 	client, err := ethclient.Dial(rpcURL)
@@ -49,24 +50,14 @@ func main() {
 	fmt.Println()
 
 	// Step 2: 配置账户信息
-	// 从配置文件获取私钥
-	privateKeyHex := config.GetTestPrivateKey()
-	
-	// 解析私钥
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	// 从配置文件加载签名账户（keystore或显式的insecure_dev_key）
+	signer, err := eth.NewSignerFromConfig(config.GetAccountsConfig())
 	if err != nil {
-		log.Fatalf("❌ 私钥解析失败: %v", err)
-	}
-
-	// 从私钥获取公钥和地址
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatal("❌ 无法获取公钥")
+		log.Fatalf("❌ 加载签名账户失败: %v", err)
 	}
 
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	fmt.Printf("🔐 已加载测试私钥，账户地址: %s\n", fromAddress.Hex())
+	fromAddress := signer.Address()
+	fmt.Printf("🔐 已加载签名账户: %s\n", fromAddress.Hex())
 	fmt.Printf("📧 发送方地址: %s\n", fromAddress.Hex())
 
 	// 接收方地址（示例地址）
@@ -152,7 +143,7 @@ func main() {
 	// Step 7: 签名交易
 	fmt.Println("✍️  签名交易...")
 
-	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), privateKey)
+	signedTx, err := signer.SignTx(tx, chainID)
 	if err != nil {
 		log.Fatalf("❌ 交易签名失败: %v", err)
 	}
@@ -185,8 +176,11 @@ func main() {
 		fmt.Printf("📋 Gas使用量: %d\n", receipt.GasUsed)
 	} else {
 		fmt.Printf("❌ 交易失败!\n")
+		// 复用已建立的连接重放交易，解码回滚原因
+		reason := eth.NewClientFromBackend(client).FailureReason(context.Background(), signedTx.Hash().Hex())
+		fmt.Printf("📋 失败原因: %s\n", reason)
 	}
 	fmt.Println()
 
 	fmt.Println("=== 交易发送示例完成 ===")
-}
\ No newline at end of file
+}
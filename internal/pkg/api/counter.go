@@ -0,0 +1,223 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// CounterResponse 计数器合约状态响应
+type CounterResponse struct {
+	Address string `json:"address"`
+	Count   string `json:"count"`
+	Owner   string `json:"owner"`
+}
+
+// CounterTxResponse 计数器交易结果响应，交易执行失败时附带回滚原因
+type CounterTxResponse struct {
+	*eth.CounterResult
+	RevertReason string `json:"revertReason,omitempty"`
+}
+
+// counterAmountRequest increment/decrement 请求体，amount可以是JSON数字或十进制字符串
+type counterAmountRequest struct {
+	Amount json.Number `json:"amount"`
+}
+
+// counterOwnerRequest transfer-ownership 请求体
+type counterOwnerRequest struct {
+	NewOwner string `json:"newOwner"`
+}
+
+// SetCounter 启用 /counter 接口，写操作使用counter的签名账户发送交易
+// 写接口只在配置了 server.counter_writes 时开放，且需携带访问令牌
+func (s *Server) SetCounter(counter *eth.CounterService) {
+	s.counter = counter
+}
+
+// handleCounter 处理 /counter 下的接口：
+// GET /counter、GET /counter/events?from=&to=、GET /counter/tx/{hash}，
+// POST /counter/increment、/counter/decrement、/counter/reset、/counter/transfer-ownership、/counter/destroy
+// 转移所有权和销毁合约影响不可逆，需额外携带 confirm 参数
+func (s *Server) handleCounter(w http.ResponseWriter, r *http.Request) {
+	if s.counter == nil {
+		writeError(w, http.StatusNotFound, "未配置计数器合约")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/counter"), "/")
+	action, rest, _ := strings.Cut(path, "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		switch {
+		case path == "":
+			s.handleCounterState(w, r)
+		case path == "events":
+			s.handleCounterEvents(w, r)
+		case action == "tx":
+			s.handleCounterTx(w, r, rest)
+		default:
+			writeError(w, http.StatusNotFound, "未知路径: %s", r.URL.Path)
+		}
+	case http.MethodPost:
+		requireBearer(s.counterToken, func(w http.ResponseWriter, r *http.Request) {
+			s.handleCounterTransact(w, r, path)
+		})(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+	}
+}
+
+// handleCounterState 处理 GET /counter
+func (s *Server) handleCounterState(w http.ResponseWriter, r *http.Request) {
+	count, err := s.counter.Count(r.Context())
+	if err != nil {
+		writeEthError(w, err)
+		return
+	}
+	owner, err := s.counter.Owner(r.Context())
+	if err != nil {
+		writeEthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CounterResponse{
+		Address: s.counter.Address().Hex(),
+		Count:   count.String(),
+		Owner:   owner.Hex(),
+	})
+}
+
+// handleCounterEvents 处理 GET /counter/events，from默认为0，to为空时查询到最新区块
+func (s *Server) handleCounterEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var from uint64
+	if value := query.Get("from"); value != "" {
+		var err error
+		if from, err = strconv.ParseUint(value, 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, "无效的from参数: %s", value)
+			return
+		}
+	}
+
+	var to *big.Int
+	if value := query.Get("to"); value != "" {
+		number, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || number < from {
+			writeError(w, http.StatusBadRequest, "无效的to参数: %s", value)
+			return
+		}
+		to = new(big.Int).SetUint64(number)
+	}
+
+	events, err := s.counter.Events(r.Context(), from, to)
+	if err != nil {
+		writeEthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// handleCounterTx 处理 GET /counter/tx/{hash}，交易未确认时返回404
+func (s *Server) handleCounterTx(w http.ResponseWriter, r *http.Request, hash string) {
+	if !isHexHash(hash) {
+		writeError(w, http.StatusBadRequest, "无效的交易哈希: %s", hash)
+		return
+	}
+
+	result, err := s.counter.Result(r.Context(), hash)
+	if result == nil {
+		writeEthError(w, err)
+		return
+	}
+
+	response := CounterTxResponse{CounterResult: result}
+	if err != nil {
+		var revert *eth.RevertError
+		if errors.As(err, &revert) && revert.Reason != "" {
+			response.RevertReason = revert.Reason
+		} else {
+			response.RevertReason = err.Error()
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleCounterTransact 处理写操作，交易发送后立即返回202，结果通过 /counter/tx/{hash} 查询
+func (s *Server) handleCounterTransact(w http.ResponseWriter, r *http.Request, action string) {
+	if s.counter.Signer() == nil {
+		writeError(w, http.StatusForbidden, "服务器未配置签名账户，不支持写操作")
+		return
+	}
+
+	var (
+		tx  *eth.Transaction
+		err error
+	)
+	switch action {
+	case "increment", "decrement":
+		var req counterAmountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "无效的请求体: %v", err)
+			return
+		}
+		amount, ok := new(big.Int).SetString(req.Amount.String(), 10)
+		if !ok || amount.Sign() < 0 {
+			writeError(w, http.StatusBadRequest, "无效的amount: %s", req.Amount)
+			return
+		}
+
+		if action == "increment" {
+			tx, err = s.counter.Increment(r.Context(), amount, eth.TxRequest{})
+		} else {
+			tx, err = s.counter.Decrement(r.Context(), amount, eth.TxRequest{})
+		}
+	case "reset":
+		tx, err = s.counter.Reset(r.Context(), eth.TxRequest{})
+	case "transfer-ownership":
+		if !confirmed(w, r, action) {
+			return
+		}
+		var req counterOwnerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "无效的请求体: %v", err)
+			return
+		}
+		if !common.IsHexAddress(req.NewOwner) {
+			writeError(w, http.StatusBadRequest, "无效的newOwner地址: %s", req.NewOwner)
+			return
+		}
+		tx, err = s.counter.TransferOwnership(r.Context(), common.HexToAddress(req.NewOwner), eth.TxRequest{})
+	case "destroy":
+		if !confirmed(w, r, action) {
+			return
+		}
+		tx, err = s.counter.Destroy(r.Context(), eth.TxRequest{})
+	default:
+		writeError(w, http.StatusNotFound, "未知路径: %s", r.URL.Path)
+		return
+	}
+
+	if err != nil {
+		writeEthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, tx)
+}
+
+// confirmed 检查不可逆操作是否带有 confirm=<操作名> 查询参数，未确认时返回400
+func confirmed(w http.ResponseWriter, r *http.Request, action string) bool {
+	if r.URL.Query().Get("confirm") != action {
+		writeError(w, http.StatusBadRequest, "不可逆操作需携带查询参数 confirm=%s", action)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+const (
+	counterABIPath = "../../../contracts/build/Counter.abi"
+	counterBinPath = "../../../contracts/build/contracts_Counter_sol_SimpleCounter.bin"
+
+	testTokenEnv = "COUNTER_API_TEST_TOKEN"
+	testToken    = "s3cret"
+)
+
+// newCounterServer 在模拟链上部署计数器合约，返回提供 /counter 接口的服务器
+// writes为true时开启写接口，withSigner为false时服务没有签名账户
+func newCounterServer(t *testing.T, writes, withSigner bool) (*Server, *simulated.Backend) {
+	t.Helper()
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	signer, err := eth.NewPrivateKeySigner(hex.EncodeToString(crypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatalf("创建签名账户失败: %v", err)
+	}
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		signer.Address(): {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)},
+	})
+	t.Cleanup(func() { backend.Close() })
+	// 创世区块不支持PUSH0，先出一个块
+	backend.Commit()
+	client := eth.NewClientFromBackend(backend.Client())
+
+	contractABI, err := eth.LoadABI(counterABIPath)
+	if err != nil {
+		t.Fatalf("加载ABI失败: %v", err)
+	}
+	bin, err := os.ReadFile(counterBinPath)
+	if err != nil {
+		t.Fatalf("读取字节码失败: %v", err)
+	}
+	contract, _, err := eth.DeployContract(ctx, client, signer, contractABI, common.FromHex(strings.TrimSpace(string(bin))), eth.TxRequest{GasLimit: 3000000})
+	if err != nil {
+		t.Fatalf("部署合约失败: %v", err)
+	}
+	backend.Commit()
+
+	var serviceSigner eth.Signer
+	if withSigner {
+		serviceSigner = signer
+	}
+	counter, err := eth.NewCounterService(client, contract.Address(), contractABI, serviceSigner)
+	if err != nil {
+		t.Fatalf("创建CounterService失败: %v", err)
+	}
+
+	t.Setenv(testTokenEnv, testToken)
+	server, err := NewServer(client, config.ServerConfig{CounterWrites: writes, CounterTokenEnv: testTokenEnv})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	server.SetCounter(counter)
+	return server, backend
+}
+
+// post 向服务器发送带令牌的POST请求，token为空时不携带 Authorization
+func post(s *Server, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+// writePaths 每个写接口带确认参数的有效请求
+var writePaths = []struct{ path, body string }{
+	{"/counter/increment", `{"amount": 1}`},
+	{"/counter/reset", ``},
+	{"/counter/transfer-ownership?confirm=transfer-ownership", `{"newOwner": "0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20"}`},
+	{"/counter/destroy?confirm=destroy", ``},
+}
+
+func TestCounterWritesDisabledByDefault(t *testing.T) {
+	s, _ := newCounterServer(t, false, true)
+
+	for _, w := range writePaths {
+		if rec := post(s, w.path, testToken, w.body); rec.Code != http.StatusForbidden {
+			t.Errorf("未开启写接口时 POST %s 返回 %d, want 403", w.path, rec.Code)
+		}
+	}
+
+	// 读接口不受影响
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/counter", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /counter 返回 %d, want 200", rec.Code)
+	}
+}
+
+func TestCounterWritesRequireToken(t *testing.T) {
+	s, _ := newCounterServer(t, true, true)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"缺少令牌", ""},
+		{"令牌错误", "wrong"},
+	}
+	for _, tt := range tests {
+		for _, w := range writePaths {
+			t.Run(tt.name+w.path, func(t *testing.T) {
+				rec := post(s, w.path, tt.token, w.body)
+				if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != "Bearer" {
+					t.Errorf("返回 %d %v, want 401", rec.Code, rec.Header())
+				}
+			})
+		}
+	}
+}
+
+func TestCounterWritesWithoutSigner(t *testing.T) {
+	s, _ := newCounterServer(t, true, false)
+
+	if rec := post(s, "/counter/increment", testToken, `{"amount": 1}`); rec.Code != http.StatusForbidden {
+		t.Errorf("没有签名账户时返回 %d, want 403", rec.Code)
+	}
+}
+
+func TestCounterTransact(t *testing.T) {
+	s, backend := newCounterServer(t, true, true)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{"请求体无效", "/counter/increment", `{"amount":`, http.StatusBadRequest},
+		{"amount为负数", "/counter/increment", `{"amount": "-1"}`, http.StatusBadRequest},
+		{"amount不是整数", "/counter/decrement", `{"amount": "1.5"}`, http.StatusBadRequest},
+		{"销毁合约未确认", "/counter/destroy", ``, http.StatusBadRequest},
+		{"销毁合约确认参数不符", "/counter/destroy?confirm=true", ``, http.StatusBadRequest},
+		{"转移所有权未确认", "/counter/transfer-ownership", `{"newOwner": "0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20"}`, http.StatusBadRequest},
+		{"newOwner无效", "/counter/transfer-ownership?confirm=transfer-ownership", `{"newOwner": "0x1234"}`, http.StatusBadRequest},
+		{"未知操作", "/counter/pause", ``, http.StatusNotFound},
+		{"增加计数", "/counter/increment", `{"amount": "5"}`, http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := post(s, tt.path, testToken, tt.body); rec.Code != tt.want {
+				t.Errorf("返回 %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+
+	backend.Commit()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/counter", nil))
+	var state CounterResponse
+	if err := json.NewDecoder(rec.Body).Decode(&state); err != nil || state.Count != "5" {
+		t.Errorf("计数 = %+v (%v), want 5", state, err)
+	}
+}
+
+// counterState 查询 GET /counter
+func counterState(t *testing.T, s *Server) (int, CounterResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/counter", nil))
+	var state CounterResponse
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&state); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+	}
+	return rec.Code, state
+}
+
+func TestCounterTransferOwnership(t *testing.T) {
+	s, backend := newCounterServer(t, true, true)
+	newOwner := common.HexToAddress("0x742d35cc6634c0532925a3b8ffb8a2b15a3f2f20").Hex()
+
+	rec := post(s, "/counter/transfer-ownership?confirm=transfer-ownership", testToken, `{"newOwner": "`+newOwner+`"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("转移所有权返回 %d %s, want 202", rec.Code, rec.Body)
+	}
+	backend.Commit()
+
+	if code, state := counterState(t, s); code != http.StatusOK || state.Owner != newOwner {
+		t.Fatalf("GET /counter = %d %+v, want 所有者 %s", code, state, newOwner)
+	}
+	// 不再是所有者，销毁合约在发送前被拒绝
+	if rec := post(s, "/counter/destroy?confirm=destroy", testToken, ``); rec.Code != http.StatusForbidden {
+		t.Errorf("非所有者销毁合约返回 %d %s, want 403", rec.Code, rec.Body)
+	}
+}
+
+func TestCounterDestroy(t *testing.T) {
+	s, backend := newCounterServer(t, true, true)
+
+	rec := post(s, "/counter/destroy?confirm=destroy", testToken, ``)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("销毁合约返回 %d %s, want 202", rec.Code, rec.Body)
+	}
+	var tx eth.Transaction
+	if err := json.NewDecoder(rec.Body).Decode(&tx); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	backend.Commit()
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/counter/tx/"+tx.Hash, nil))
+	var result CounterTxResponse
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil || rec.Code != http.StatusOK || result.Status != 1 {
+		t.Errorf("销毁交易结果 = %d %+v (%v), want 执行成功", rec.Code, result, err)
+	}
+}
+
+func TestNewServerRequiresCounterToken(t *testing.T) {
+	t.Setenv(testTokenEnv, "")
+	if _, err := NewServer(nil, config.ServerConfig{CounterWrites: true, CounterTokenEnv: testTokenEnv}); err == nil {
+		t.Error("开启写接口但未设置令牌时应返回错误")
+	}
+	if _, err := NewServer(nil, config.ServerConfig{CounterWrites: true}); err == nil {
+		t.Error("开启写接口但未配置 counter_token_env 时应返回错误")
+	}
+}
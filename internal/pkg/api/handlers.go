@@ -0,0 +1,189 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go-eth-backend/internal/pkg/cache"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// BalanceResponse 账户余额响应
+type BalanceResponse struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+}
+
+// NonceResponse 账户nonce响应
+type NonceResponse struct {
+	Address string `json:"address"`
+	Nonce   uint64 `json:"nonce"`
+}
+
+// GasPriceResponse gas价格响应
+type GasPriceResponse struct {
+	GasPrice string `json:"gasPrice"`
+}
+
+// handleBlocks 处理 /blocks/latest、/blocks/{number} 和 /blocks/hash/{hash}
+// 查询参数 full=true 时返回完整交易
+func (s *Server) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+		return
+	}
+
+	fullTx := false
+	if full := r.URL.Query().Get("full"); full != "" {
+		var err error
+		if fullTx, err = strconv.ParseBool(full); err != nil {
+			writeError(w, http.StatusBadRequest, "无效的full参数: %s", full)
+			return
+		}
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/blocks/")
+	var (
+		block *eth.JSONBlock
+		err   error
+	)
+
+	switch {
+	case path == "latest":
+		block, err = s.client.GetLatestJSONBlock(r.Context(), fullTx)
+	case strings.HasPrefix(path, "hash/"):
+		hash := strings.TrimPrefix(path, "hash/")
+		if !isHexHash(hash) {
+			writeError(w, http.StatusBadRequest, "无效的区块哈希: %s", hash)
+			return
+		}
+		block, err = s.client.GetJSONBlockByHash(r.Context(), hash, fullTx)
+	default:
+		number, parseErr := strconv.ParseUint(path, 10, 64)
+		if parseErr != nil {
+			writeError(w, http.StatusBadRequest, "无效的区块号: %s", path)
+			return
+		}
+		block, err = s.client.GetJSONBlockByNumber(r.Context(), number, fullTx)
+	}
+
+	if err != nil {
+		writeEthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, block)
+}
+
+// handleTransactions 处理 /tx/{hash} 和 /tx/{hash}/receipt
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/tx/")
+	hash, rest, _ := strings.Cut(path, "/")
+	if !isHexHash(hash) {
+		writeError(w, http.StatusBadRequest, "无效的交易哈希: %s", hash)
+		return
+	}
+
+	switch rest {
+	case "":
+		tx, isPending, err := s.client.GetTransactionByHash(r.Context(), hash)
+		if err != nil {
+			writeEthError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, eth.ParseTransaction(tx, isPending))
+	case "receipt":
+		receipt, err := s.client.GetTransactionReceipt(r.Context(), hash)
+		if err != nil {
+			writeEthError(w, err)
+			return
+		}
+		// 收据不含发送方和接收方，从交易中恢复
+		tx, _, err := s.client.GetTransactionByHash(r.Context(), hash)
+		if err != nil {
+			writeEthError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, eth.ParseReceipt(receipt, tx))
+	default:
+		writeError(w, http.StatusNotFound, "未知路径: %s", r.URL.Path)
+	}
+}
+
+// handleAccounts 处理 /accounts/{addr}/balance 和 /accounts/{addr}/nonce
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/accounts/")
+	address, rest, _ := strings.Cut(path, "/")
+	if !common.IsHexAddress(address) {
+		writeError(w, http.StatusBadRequest, "无效的账户地址: %s", address)
+		return
+	}
+	address = common.HexToAddress(address).Hex()
+
+	switch rest {
+	case "balance":
+		balance, err := s.client.GetBalance(r.Context(), address)
+		if err != nil {
+			writeEthError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, BalanceResponse{Address: address, Balance: balance.String()})
+	case "nonce":
+		nonce, err := s.client.GetNonce(r.Context(), address)
+		if err != nil {
+			writeEthError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, NonceResponse{Address: address, Nonce: nonce})
+	default:
+		writeError(w, http.StatusNotFound, "未知路径: %s", r.URL.Path)
+	}
+}
+
+// handleGasPrice 处理 /gas-price
+func (s *Server) handleGasPrice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+		return
+	}
+
+	gasPrice, err := s.client.GetGasPrice(r.Context())
+	if err != nil {
+		writeEthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, GasPriceResponse{GasPrice: gasPrice.String()})
+}
+
+// handleCacheStats 处理 /cache/stats，未启用缓存时返回404
+func (s *Server) handleCacheStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+		return
+	}
+
+	cached, ok := s.client.(interface{ Stats() cache.Stats })
+	if !ok {
+		writeError(w, http.StatusNotFound, "未启用缓存")
+		return
+	}
+	writeJSON(w, http.StatusOK, cached.Stats())
+}
+
+// isHexHash 检查字符串是否为0x前缀的32字节十六进制哈希
+func isHexHash(s string) bool {
+	b, err := hexutil.Decode(s)
+	return err == nil && len(b) == common.HashLength
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// errReader 所有查询都返回err的eth.Reader
+type errReader struct {
+	err error
+}
+
+func (r errReader) GetLatestBlockNumber(ctx context.Context) (uint64, error) { return 0, r.err }
+func (r errReader) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	return 0, r.err
+}
+func (r errReader) GetLatestJSONBlock(ctx context.Context, fullTx bool) (*eth.JSONBlock, error) {
+	return nil, r.err
+}
+func (r errReader) GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*eth.JSONBlock, error) {
+	return nil, r.err
+}
+func (r errReader) GetJSONBlockByHash(ctx context.Context, hash string, fullTx bool) (*eth.JSONBlock, error) {
+	return nil, r.err
+}
+func (r errReader) GetTransactionByHash(ctx context.Context, hash string) (*types.Transaction, bool, error) {
+	return nil, false, r.err
+}
+func (r errReader) GetTransactionReceipt(ctx context.Context, hash string) (*types.Receipt, error) {
+	return nil, r.err
+}
+func (r errReader) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	return nil, r.err
+}
+func (r errReader) GetNonce(ctx context.Context, address string) (uint64, error) { return 0, r.err }
+func (r errReader) GetGasPrice(ctx context.Context) (*big.Int, error)            { return nil, r.err }
+
+// serve 向服务器发送不带请求体的请求
+func serve(s *Server, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func newTestServer(t *testing.T, client eth.Reader) *Server {
+	t.Helper()
+	s, err := NewServer(client, config.ServerConfig{})
+	if err != nil {
+		t.Fatalf("创建服务器失败: %v", err)
+	}
+	return s
+}
+
+func TestQueryRoutes(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	signer, err := eth.NewPrivateKeySigner(hex.EncodeToString(crypto.FromECDSA(key)))
+	if err != nil {
+		t.Fatalf("创建签名账户失败: %v", err)
+	}
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		signer.Address(): {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)},
+	})
+	t.Cleanup(func() { backend.Close() })
+	client := eth.NewClientFromBackend(backend.Client())
+
+	// 在区块1中打包一笔转账
+	to := "0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20"
+	txHash, err := client.SendTransaction(ctx, signer, to, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	blockHash := backend.Commit().Hex()
+	missing := common.HexToHash("0x01").Hex()
+	sender := signer.Address().Hex()
+
+	s := newTestServer(t, client)
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+		body   string // 响应中应包含的内容
+	}{
+		{"最新区块", http.MethodGet, "/blocks/latest", http.StatusOK, `"number":1`},
+		{"按区块号查询完整交易", http.MethodGet, "/blocks/1?full=true", http.StatusOK, txHash},
+		{"按区块哈希查询", http.MethodGet, "/blocks/hash/" + blockHash, http.StatusOK, blockHash},
+		{"区块号无效", http.MethodGet, "/blocks/abc", http.StatusBadRequest, "无效的区块号"},
+		{"full参数无效", http.MethodGet, "/blocks/1?full=maybe", http.StatusBadRequest, "无效的full参数"},
+		{"区块哈希无效", http.MethodGet, "/blocks/hash/0x1234", http.StatusBadRequest, "无效的区块哈希"},
+		{"区块不存在", http.MethodGet, "/blocks/99", http.StatusNotFound, ""},
+		{"区块哈希不存在", http.MethodGet, "/blocks/hash/" + missing, http.StatusNotFound, ""},
+		{"区块不支持POST", http.MethodPost, "/blocks/latest", http.StatusMethodNotAllowed, ""},
+
+		{"交易", http.MethodGet, "/tx/" + txHash, http.StatusOK, txHash},
+		{"交易收据", http.MethodGet, "/tx/" + txHash + "/receipt", http.StatusOK, `"status":1`},
+		{"交易哈希无效", http.MethodGet, "/tx/0xzz", http.StatusBadRequest, "无效的交易哈希"},
+		{"交易不存在", http.MethodGet, "/tx/" + missing, http.StatusNotFound, ""},
+		{"收据不存在", http.MethodGet, "/tx/" + missing + "/receipt", http.StatusNotFound, ""},
+		{"交易未知子路径", http.MethodGet, "/tx/" + txHash + "/logs", http.StatusNotFound, "未知路径"},
+		{"交易不支持DELETE", http.MethodDelete, "/tx/" + txHash, http.StatusMethodNotAllowed, ""},
+
+		{"余额", http.MethodGet, "/accounts/" + to + "/balance", http.StatusOK, `"balance":"1"`},
+		{"nonce", http.MethodGet, "/accounts/" + strings.ToLower(sender) + "/nonce", http.StatusOK, fmt.Sprintf(`"address":"%s","nonce":1`, sender)},
+		{"账户地址无效", http.MethodGet, "/accounts/0x1234/balance", http.StatusBadRequest, "无效的账户地址"},
+		{"账户未知子路径", http.MethodGet, "/accounts/" + to + "/code", http.StatusNotFound, "未知路径"},
+		{"账户不支持PUT", http.MethodPut, "/accounts/" + to + "/balance", http.StatusMethodNotAllowed, ""},
+
+		{"gas价格", http.MethodGet, "/gas-price", http.StatusOK, `"gasPrice"`},
+		{"gas价格不支持POST", http.MethodPost, "/gas-price", http.StatusMethodNotAllowed, ""},
+
+		{"未启用缓存", http.MethodGet, "/cache/stats", http.StatusNotFound, "未启用缓存"},
+		{"缓存统计不支持POST", http.MethodPost, "/cache/stats", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, tt.method, tt.path)
+			if rec.Code != tt.want || !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("%s %s = %d %s, want %d 且包含 %s", tt.method, tt.path, rec.Code, rec.Body, tt.want, tt.body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+		})
+	}
+}
+
+func TestQueryRoutesMapNodeErrors(t *testing.T) {
+	hash := common.HexToHash("0x01").Hex()
+	address := "0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20"
+	paths := []string{
+		"/blocks/latest",
+		"/blocks/1",
+		"/blocks/hash/" + hash,
+		"/tx/" + hash,
+		"/tx/" + hash + "/receipt",
+		"/accounts/" + address + "/balance",
+		"/accounts/" + address + "/nonce",
+		"/gas-price",
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"不存在", fmt.Errorf("查询失败: %w", eth.ErrNotFound), http.StatusNotFound},
+		{"节点不可用", fmt.Errorf("查询失败: %w", eth.ErrRPCUnavailable), http.StatusServiceUnavailable},
+		{"超时", fmt.Errorf("查询失败: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"其他错误", fmt.Errorf("查询失败"), http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		s := newTestServer(t, errReader{err: tt.err})
+		for _, path := range paths {
+			t.Run(tt.name+path, func(t *testing.T) {
+				if rec := serve(s, http.MethodGet, path); rec.Code != tt.want || !strings.Contains(rec.Body.String(), "查询失败") {
+					t.Errorf("GET %s = %d %s, want %d", path, rec.Code, rec.Body, tt.want)
+				}
+			})
+		}
+	}
+}
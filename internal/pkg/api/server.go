@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// Server REST API服务器，将eth.Reader的查询能力以JSON形式对外提供
+type Server struct {
+	client  eth.Reader
+	counter *eth.CounterService
+	// counterToken 计数器写接口的访问令牌，为空表示未开启写接口
+	counterToken string
+	httpServer   *http.Server
+}
+
+// NewServer 根据服务器配置创建REST API服务器
+// client 可以是 *eth.Client，也可以是 cache.Client 等装饰器
+func NewServer(client eth.Reader, cfg config.ServerConfig) (*Server, error) {
+	readTimeout, err := cfg.GetReadTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	writeTimeout, err := cfg.GetWriteTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{client: client}
+	if cfg.CounterWrites {
+		if s.counterToken, err = cfg.GetCounterToken(); err != nil {
+			return nil, err
+		}
+	}
+	s.httpServer = &http.Server{
+		Addr:         cfg.Address(),
+		Handler:      s.Handler(),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+	}
+
+	return s, nil
+}
+
+// Handler 返回注册了全部路由的HTTP处理器
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/blocks/", s.handleBlocks)
+	mux.HandleFunc("/tx/", s.handleTransactions)
+	mux.HandleFunc("/accounts/", s.handleAccounts)
+	mux.HandleFunc("/gas-price", s.handleGasPrice)
+	mux.HandleFunc("/cache/stats", s.handleCacheStats)
+	mux.HandleFunc("/counter", s.handleCounter)
+	mux.HandleFunc("/counter/", s.handleCounter)
+	return mux
+}
+
+// Addr 返回服务器监听地址
+func (s *Server) Addr() string {
+	return s.httpServer.Addr
+}
+
+// ListenAndServe 启动HTTP服务器，正常关闭时返回nil
+func (s *Server) ListenAndServe() error {
+	err := s.httpServer.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown 优雅关闭HTTP服务器
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// requireBearer 写接口的鉴权中间件：token为空表示未开启写接口，返回403；
+// 请求未携带 Authorization: Bearer <token> 或令牌不匹配时返回401
+func requireBearer(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			writeError(w, http.StatusForbidden, "写接口未开启")
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "缺少或无效的访问令牌")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// errorResponse 错误响应结构体
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeJSON 以JSON格式写出响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 以JSON格式写出错误响应
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// writeEthError 按eth包的错误类型映射HTTP状态码并写入错误响应
+func writeEthError(w http.ResponseWriter, err error) {
+	writeError(w, statusForError(err), "%v", err)
+}
+
+// statusForError 将eth包的错误类型映射为HTTP状态码
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, eth.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, eth.ErrNonceTooLow):
+		return http.StatusConflict
+	case errors.Is(err, eth.ErrNotOwner):
+		return http.StatusForbidden
+	case errors.Is(err, eth.ErrInsufficientFunds),
+		errors.Is(err, eth.ErrUnderpriced),
+		errors.Is(err, eth.ErrReverted):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, eth.ErrRPCUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,551 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/websocket"
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+const (
+	// StreamBufferSize 每个推送连接的消息缓冲大小，缓冲写满的慢速客户端会被断开
+	StreamBufferSize = 64
+	// streamPingInterval 推送连接的保活间隔
+	streamPingInterval = 15 * time.Second
+	// streamWriteTimeout WebSocket单条消息的写超时
+	streamWriteTimeout = 10 * time.Second
+)
+
+// 推送连接被服务端关闭的原因
+const (
+	reasonSlowConsumer = "消费过慢，消息缓冲已满"
+	reasonUpstreamDone = "上游订阅已结束"
+	reasonShutdown     = "服务器关闭"
+)
+
+// errStreamClosed 推送服务已关闭，不再接受新的订阅
+var errStreamClosed = errors.New(reasonShutdown)
+
+// StreamSource 推送服务依赖的订阅能力，由 *eth.Client 实现
+type StreamSource interface {
+	SubscribeNewHeads(ctx context.Context) (<-chan *eth.BlockHeader, error)
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery) (<-chan types.Log, error)
+	GetJSONBlockByHash(ctx context.Context, hash string, fullTx bool) (*eth.JSONBlock, error)
+}
+
+// StreamServer 以SSE或WebSocket推送新区块和合约事件的HTTP服务器
+// 相同的订阅（全部区块，或相同地址和topic的事件）在所有连接间共享一个上游订阅；
+// 每个连接有独立的消息缓冲，缓冲写满时断开该连接并告知原因，不会阻塞其他连接
+type StreamServer struct {
+	source     StreamSource
+	abis       []abi.ABI
+	upgrader   websocket.Upgrader
+	httpServer *http.Server
+
+	mu        sync.Mutex
+	feeds     map[string]*feed
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// NewStreamServer 创建推送服务器，监听 server.stream_port
+// 推送连接是长连接，因此不设置写超时
+func NewStreamServer(source StreamSource, cfg config.ServerConfig) (*StreamServer, error) {
+	readTimeout, err := cfg.GetReadTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StreamServer{
+		source: source,
+		// 面板页面通常与API不同源，允许跨域建立WebSocket连接
+		upgrader: websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }},
+		feeds:    make(map[string]*feed),
+		closing:  make(chan struct{}),
+	}
+	s.httpServer = &http.Server{
+		Addr:        cfg.StreamAddress(),
+		Handler:     s.Handler(),
+		ReadTimeout: readTimeout,
+	}
+
+	return s, nil
+}
+
+// RegisterABI 注册用于解码推送事件的合约ABI，需在启动服务前调用
+// 无法用已注册ABI解码的日志以原始topics和data推送
+func (s *StreamServer) RegisterABI(contractABI abi.ABI) {
+	s.abis = append(s.abis, contractABI)
+}
+
+// Handler 返回推送服务的HTTP处理器
+func (s *StreamServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream/blocks", s.handleBlocks)
+	mux.HandleFunc("/stream/events", s.handleEvents)
+	return mux
+}
+
+// Addr 返回推送服务监听地址
+func (s *StreamServer) Addr() string {
+	return s.httpServer.Addr
+}
+
+// ListenAndServe 启动推送服务，正常关闭时返回nil
+func (s *StreamServer) ListenAndServe() error {
+	err := s.httpServer.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown 通知所有推送连接关闭，并取消全部上游订阅
+func (s *StreamServer) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.closing) })
+
+	s.mu.Lock()
+	for key, f := range s.feeds {
+		f.cancel()
+		delete(s.feeds, key)
+	}
+	s.mu.Unlock()
+
+	return s.httpServer.Shutdown(ctx)
+}
+
+// message 推送给客户端的消息，SSE中Event为事件类型，WebSocket中整体作为JSON发送
+type message struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// closeMessage 服务端主动断开时发送的原因
+type closeMessage struct {
+	Reason string `json:"reason"`
+}
+
+// feed 共享的上游订阅，将消息分发给所有订阅者
+type feed struct {
+	key         string
+	cancel      context.CancelFunc
+	subscribers map[*subscriber]struct{}
+	ready       chan struct{} // 上游建立完成或失败时关闭
+	err         error         // 建立上游失败的原因，ready关闭后可读
+}
+
+// subscriber 单个推送连接的消息缓冲
+type subscriber struct {
+	messages chan message
+	dropped  chan struct{} // 被服务端断开时关闭
+	reason   string        // 断开原因，dropped关闭后可读
+}
+
+// upstreamFunc 建立上游订阅，返回的通道在ctx取消或订阅结束时关闭
+type upstreamFunc func(ctx context.Context) (<-chan message, error)
+
+// handleBlocks 处理 /stream/blocks，推送新区块（JSONBlock）
+func (s *StreamServer) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+		return
+	}
+
+	s.serve(w, r, "blocks", s.blockUpstream)
+}
+
+// handleEvents 处理 /stream/events?address=&topic=，推送合约事件
+// address 必填，address 和 topic 均可重复指定，同一参数的多个值之间为"或"关系；topic 匹配事件签名（topics[0]）
+func (s *StreamServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "不支持的请求方法: %s", r.Method)
+		return
+	}
+
+	query, key, err := parseEventFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	s.serve(w, r, key, func(ctx context.Context) (<-chan message, error) {
+		return s.eventUpstream(ctx, query)
+	})
+}
+
+// serve 订阅共享上游并按请求类型以WebSocket或SSE推送
+func (s *StreamServer) serve(w http.ResponseWriter, r *http.Request, key string, upstream upstreamFunc) {
+	sub, err := s.subscribe(key, upstream)
+	if errors.Is(err, errStreamClosed) {
+		writeError(w, http.StatusServiceUnavailable, "%v", err)
+		return
+	}
+	if err != nil {
+		writeEthError(w, err)
+		return
+	}
+	defer s.unsubscribe(key, sub)
+
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebSocket(w, r, sub)
+	} else {
+		s.serveSSE(w, r, sub)
+	}
+}
+
+// serveSSE 以Server-Sent Events推送消息，断开前发送 close 事件说明原因
+func (s *StreamServer) serveSSE(w http.ResponseWriter, r *http.Request, sub *subscriber) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "当前连接不支持流式响应")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writeClose := func(reason string) {
+		data, _ := json.Marshal(closeMessage{Reason: reason})
+		fmt.Fprintf(w, "event: close\ndata: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			writeClose(reasonShutdown)
+			return
+		case <-sub.dropped:
+			writeClose(sub.reason)
+			return
+		case msg := <-sub.messages:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Event, msg.Data)
+			flusher.Flush()
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// serveWebSocket 以WebSocket推送消息，断开时在close帧中说明原因
+func (s *StreamServer) serveWebSocket(w http.ResponseWriter, r *http.Request, sub *subscriber) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade 已写入错误响应
+	}
+	defer conn.Close()
+
+	// 读取并丢弃客户端消息，以便处理控制帧并感知客户端断开
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	writeClose := func(code int, reason string) {
+		deadline := time.Now().Add(streamWriteTimeout)
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+	}
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-gone:
+			return
+		case <-s.closing:
+			writeClose(websocket.CloseGoingAway, reasonShutdown)
+			return
+		case <-sub.dropped:
+			writeClose(websocket.CloseTryAgainLater, sub.reason)
+			return
+		case msg := <-sub.messages:
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe 为连接创建消息缓冲并加入共享上游，上游不存在时建立
+func (s *StreamServer) subscribe(key string, upstream upstreamFunc) (*subscriber, error) {
+	for {
+		select {
+		case <-s.closing:
+			return nil, errStreamClosed
+		default:
+		}
+
+		f, err := s.feed(key, upstream)
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		// 等待上游建立期间，上游可能已结束或因其他连接全部离开而被取消，此时重新建立
+		if s.feeds[key] == f {
+			sub := &subscriber{
+				messages: make(chan message, StreamBufferSize),
+				dropped:  make(chan struct{}),
+			}
+			f.subscribers[sub] = struct{}{}
+			s.mu.Unlock()
+			return sub, nil
+		}
+		s.mu.Unlock()
+	}
+}
+
+// feed 返回key对应的共享上游，不存在时建立
+// 建立上游需要访问节点，期间不持有s.mu，以免阻塞其他连接和消息分发；同一key的并发请求等待同一次建立
+func (s *StreamServer) feed(key string, upstream upstreamFunc) (*feed, error) {
+	s.mu.Lock()
+	if f, ok := s.feeds[key]; ok {
+		s.mu.Unlock()
+		<-f.ready
+		return f, f.err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &feed{
+		key:         key,
+		cancel:      cancel,
+		subscribers: make(map[*subscriber]struct{}),
+		ready:       make(chan struct{}),
+	}
+	s.feeds[key] = f
+	s.mu.Unlock()
+
+	messages, err := upstream(ctx)
+	if err != nil {
+		cancel()
+		s.mu.Lock()
+		if s.feeds[key] == f {
+			delete(s.feeds, key)
+		}
+		s.mu.Unlock()
+	} else {
+		go s.broadcast(f, messages)
+	}
+
+	f.err = err
+	close(f.ready)
+	return f, err
+}
+
+// unsubscribe 连接结束时移出共享上游，最后一个连接离开时取消上游订阅
+func (s *StreamServer) unsubscribe(key string, sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.feeds[key]
+	if !ok {
+		return
+	}
+	if _, ok := f.subscribers[sub]; !ok {
+		return
+	}
+	delete(f.subscribers, sub)
+	s.releaseIfIdle(f)
+}
+
+// broadcast 将上游消息分发给所有订阅者，缓冲已满的订阅者被断开
+// 上游结束后断开剩余订阅者
+func (s *StreamServer) broadcast(f *feed, messages <-chan message) {
+	for msg := range messages {
+		s.mu.Lock()
+		for sub := range f.subscribers {
+			select {
+			case sub.messages <- msg:
+			default:
+				s.drop(f, sub, reasonSlowConsumer)
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range f.subscribers {
+		s.drop(f, sub, reasonUpstreamDone)
+	}
+	// 没有订阅者时上游也可能结束，之后的连接需要重新建立上游
+	f.cancel()
+	if s.feeds[f.key] == f {
+		delete(s.feeds, f.key)
+	}
+}
+
+// drop 断开订阅者并告知原因，调用方需持有s.mu
+func (s *StreamServer) drop(f *feed, sub *subscriber, reason string) {
+	delete(f.subscribers, sub)
+	sub.reason = reason
+	close(sub.dropped)
+	s.releaseIfIdle(f)
+}
+
+// releaseIfIdle 没有订阅者时取消上游订阅，调用方需持有s.mu
+func (s *StreamServer) releaseIfIdle(f *feed) {
+	if len(f.subscribers) > 0 {
+		return
+	}
+	f.cancel()
+	if s.feeds[f.key] == f {
+		delete(s.feeds, f.key)
+	}
+}
+
+// blockUpstream 订阅新区块头并查询完整区块
+func (s *StreamServer) blockUpstream(ctx context.Context) (<-chan message, error) {
+	heads, err := s.source.SubscribeNewHeads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make(chan message)
+	go func() {
+		defer close(messages)
+		for head := range heads {
+			block, err := s.source.GetJSONBlockByHash(ctx, head.Hash, false)
+			if err != nil {
+				// 查询失败（或区块已被重组移除）时跳过，下一个区块仍会推送
+				continue
+			}
+			if !sendMessage(ctx, messages, "block", block) {
+				return
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+// eventUpstream 订阅日志并按已注册的ABI解码
+func (s *StreamServer) eventUpstream(ctx context.Context, query ethereum.FilterQuery) (<-chan message, error) {
+	logs, err := s.source.SubscribeFilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make(chan message)
+	go func() {
+		defer close(messages)
+		for log := range logs {
+			if !sendMessage(ctx, messages, "event", s.decode(log)) {
+				return
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+// decode 依次尝试已注册的ABI，均无法解码时返回未解码的事件
+func (s *StreamServer) decode(log types.Log) *eth.Event {
+	for _, contractABI := range s.abis {
+		if event, err := eth.DecodeLog(contractABI, log); err == nil {
+			return event
+		}
+	}
+	return eth.NewEvent(log)
+}
+
+// sendMessage 编码消息并发送到上游通道，ctx取消时返回false
+func sendMessage(ctx context.Context, messages chan<- message, event string, v interface{}) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case messages <- message{Event: event, Data: data}:
+		return true
+	}
+}
+
+// parseEventFilter 解析事件推送的过滤条件，并生成用于共享上游订阅的键
+// 至少需要指定一个合约地址，避免订阅全链日志；地址和topic规范化、去重并排序后组成键，
+// 同一过滤条件的不同写法（大小写、有无0x前缀、顺序）共享同一个上游订阅
+func parseEventFilter(r *http.Request) (ethereum.FilterQuery, string, error) {
+	var query ethereum.FilterQuery
+	params := r.URL.Query()
+
+	if len(params["address"]) == 0 {
+		return query, "", errors.New("至少需要指定一个合约地址 address")
+	}
+
+	addresses := make(map[string]bool)
+	for _, address := range params["address"] {
+		if !common.IsHexAddress(address) {
+			return query, "", fmt.Errorf("无效的合约地址: %s", address)
+		}
+		addresses[common.HexToAddress(address).Hex()] = true
+	}
+	addressKeys := sortedKeys(addresses)
+	for _, address := range addressKeys {
+		query.Addresses = append(query.Addresses, common.HexToAddress(address))
+	}
+
+	topics := make(map[string]bool)
+	for _, topic := range params["topic"] {
+		if !isHexHash(topic) {
+			return query, "", fmt.Errorf("无效的topic: %s", topic)
+		}
+		topics[common.HexToHash(topic).Hex()] = true
+	}
+	topicKeys := sortedKeys(topics)
+	if len(topicKeys) > 0 {
+		topic0 := make([]common.Hash, len(topicKeys))
+		for i, topic := range topicKeys {
+			topic0[i] = common.HexToHash(topic)
+		}
+		query.Topics = [][]common.Hash{topic0}
+	}
+
+	key := fmt.Sprintf("events:%s:%s", strings.Join(addressKeys, ","), strings.Join(topicKeys, ","))
+	return query, key, nil
+}
+
+// sortedKeys 返回集合中排序后的元素
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
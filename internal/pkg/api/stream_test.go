@@ -0,0 +1,316 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/websocket"
+	"go-eth-backend/internal/pkg/config"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// fakeStreamSource 由测试控制的上游订阅
+type fakeStreamSource struct {
+	heads chan *eth.BlockHeader
+	// release 非nil时建立新区块订阅会阻塞到其关闭，模拟访问节点很慢
+	release chan struct{}
+	entered chan struct{}
+
+	mu        sync.Mutex
+	headCalls int
+}
+
+func newFakeStreamSource() *fakeStreamSource {
+	return &fakeStreamSource{heads: make(chan *eth.BlockHeader), entered: make(chan struct{}, 4)}
+}
+
+func (f *fakeStreamSource) SubscribeNewHeads(ctx context.Context) (<-chan *eth.BlockHeader, error) {
+	f.mu.Lock()
+	f.headCalls++
+	f.mu.Unlock()
+
+	f.entered <- struct{}{}
+	if f.release != nil {
+		<-f.release
+	}
+	return f.heads, nil
+}
+
+func (f *fakeStreamSource) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery) (<-chan types.Log, error) {
+	return make(chan types.Log), nil
+}
+
+func (f *fakeStreamSource) GetJSONBlockByHash(ctx context.Context, hash string, fullTx bool) (*eth.JSONBlock, error) {
+	return &eth.JSONBlock{BlockHeader: eth.BlockHeader{Hash: hash}}, nil
+}
+
+func newTestStreamServer(t *testing.T, source StreamSource) *StreamServer {
+	t.Helper()
+	s, err := NewStreamServer(source, config.ServerConfig{})
+	if err != nil {
+		t.Fatalf("创建推送服务器失败: %v", err)
+	}
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s
+}
+
+// waitDropped 等待订阅者被服务端断开并返回原因
+func waitDropped(t *testing.T, sub *subscriber) string {
+	t.Helper()
+	select {
+	case <-sub.dropped:
+		return sub.reason
+	case <-time.After(5 * time.Second):
+		t.Fatal("订阅者没有被断开")
+		return ""
+	}
+}
+
+func TestStreamDropsSlowConsumer(t *testing.T) {
+	source := newFakeStreamSource()
+	s := newTestStreamServer(t, source)
+
+	slow, err := s.subscribe("blocks", s.blockUpstream)
+	if err != nil {
+		t.Fatalf("订阅失败: %v", err)
+	}
+	fast, err := s.subscribe("blocks", s.blockUpstream)
+	if err != nil {
+		t.Fatalf("订阅失败: %v", err)
+	}
+	if source.headCalls != 1 {
+		t.Fatalf("建立上游订阅 %d 次, want 1", source.headCalls)
+	}
+
+	// fast 持续读取，slow 不读取直到缓冲写满
+	received := make(chan int)
+	go func() {
+		n := 0
+		for {
+			select {
+			case <-fast.messages:
+				n++
+			case <-fast.dropped:
+				received <- n
+				return
+			}
+		}
+	}()
+	for i := 0; i <= StreamBufferSize; i++ {
+		source.heads <- &eth.BlockHeader{Hash: "0x01"}
+	}
+
+	if reason := waitDropped(t, slow); reason != reasonSlowConsumer {
+		t.Errorf("断开原因 = %q, want %q", reason, reasonSlowConsumer)
+	}
+	select {
+	case <-fast.dropped:
+		t.Fatal("正常消费的订阅者不应被断开")
+	default:
+	}
+
+	// 上游结束后断开剩余订阅者
+	close(source.heads)
+	if reason := waitDropped(t, fast); reason != reasonUpstreamDone {
+		t.Errorf("断开原因 = %q, want %q", reason, reasonUpstreamDone)
+	}
+	if n := <-received; n != StreamBufferSize+1 {
+		t.Errorf("正常消费的订阅者收到 %d 条消息, want %d", n, StreamBufferSize+1)
+	}
+}
+
+func TestStreamUpstreamSetupDoesNotHoldLock(t *testing.T) {
+	source := newFakeStreamSource()
+	source.release = make(chan struct{})
+	s := newTestStreamServer(t, source)
+
+	type result struct {
+		sub *subscriber
+		err error
+	}
+	results := make(chan result, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			sub, err := s.subscribe("blocks", s.blockUpstream)
+			results <- result{sub, err}
+		}()
+	}
+	<-source.entered
+
+	// 新区块订阅建立期间，其他订阅不受影响
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.subscribe("events::", func(ctx context.Context) (<-chan message, error) {
+			return s.eventUpstream(ctx, ethereum.FilterQuery{})
+		})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("订阅事件失败: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("建立上游期间其他订阅被阻塞")
+	}
+
+	close(source.release)
+	for i := 0; i < 2; i++ {
+		if r := <-results; r.err != nil || r.sub == nil {
+			t.Fatalf("订阅新区块失败: %v", r.err)
+		}
+	}
+	if source.headCalls != 1 {
+		t.Errorf("并发订阅建立上游 %d 次, want 1", source.headCalls)
+	}
+}
+
+func TestStreamSSECloseReason(t *testing.T) {
+	source := newFakeStreamSource()
+	s := newTestStreamServer(t, source)
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/stream/blocks")
+	if err != nil {
+		t.Fatalf("连接推送服务失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	source.heads <- &eth.BlockHeader{Hash: "0xabc"}
+	close(source.heads)
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, string) {
+		var event, data string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("读取SSE失败: %v", err)
+			}
+			line = strings.TrimSpace(line)
+			switch {
+			case line == "" && event != "":
+				return event, data
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}
+
+	if event, data := readEvent(); event != "block" || !strings.Contains(data, `"hash":"0xabc"`) {
+		t.Errorf("第一条消息 = %s %s, want block", event, data)
+	}
+	event, data := readEvent()
+	var closed closeMessage
+	if err := json.Unmarshal([]byte(data), &closed); err != nil || event != "close" || closed.Reason != reasonUpstreamDone {
+		t.Errorf("关闭消息 = %s %s, want close %q", event, data, reasonUpstreamDone)
+	}
+}
+
+func TestStreamWebSocketCloseReason(t *testing.T) {
+	source := newFakeStreamSource()
+	s := newTestStreamServer(t, source)
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/stream/blocks", nil)
+	if err != nil {
+		t.Fatalf("建立WebSocket连接失败: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	source.heads <- &eth.BlockHeader{Hash: "0xabc"}
+	var msg message
+	if err := conn.ReadJSON(&msg); err != nil || msg.Event != "block" {
+		t.Fatalf("读取消息 = %+v, %v", msg, err)
+	}
+
+	close(source.heads)
+	_, _, err = conn.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	if !ok || closeErr.Code != websocket.CloseTryAgainLater || closeErr.Text != reasonUpstreamDone {
+		t.Errorf("关闭帧 = %v, want %d %q", err, websocket.CloseTryAgainLater, reasonUpstreamDone)
+	}
+}
+
+func TestStreamRejectsAfterShutdown(t *testing.T) {
+	s := newTestStreamServer(t, newFakeStreamSource())
+	s.Shutdown(context.Background())
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream/blocks", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("关闭后订阅返回 %d, want 503", rec.Code)
+	}
+}
+
+func TestParseEventFilter(t *testing.T) {
+	const (
+		address = "0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20"
+		other   = "0x0000000000000000000000000000000000000001"
+		topic   = "0xb0b1ddbbf6c01b1c7b0fa1c6fc3bc1e3a2bfc4a4e9ae7aa59f8c7d2f0cd0a3c5"
+	)
+	parse := func(rawQuery string) (ethereum.FilterQuery, string, error) {
+		return parseEventFilter(httptest.NewRequest(http.MethodGet, "/stream/events?"+rawQuery, nil))
+	}
+
+	_, want, err := parse("address=" + address + "&address=" + other + "&topic=" + topic)
+	if err != nil {
+		t.Fatalf("解析过滤条件失败: %v", err)
+	}
+
+	// 大小写、0x前缀、顺序和重复不同的同一过滤条件使用同一个键
+	for _, rawQuery := range []string{
+		"address=" + strings.ToLower(address) + "&address=" + other + "&topic=" + topic,
+		"address=" + other + "&address=" + strings.TrimPrefix(address, "0x") + "&topic=0x" + strings.ToUpper(topic[2:]) + "&topic=" + topic,
+		"address=0x" + strings.ToUpper(address[2:]) + "&address=" + other + "&address=" + address + "&topic=0x" + strings.ToUpper(topic[2:]),
+	} {
+		query, key, err := parse(rawQuery)
+		if err != nil {
+			t.Fatalf("解析 %s 失败: %v", rawQuery, err)
+		}
+		if key != want {
+			t.Errorf("%s 的键 = %s, want %s", rawQuery, key, want)
+		}
+		if len(query.Addresses) != 2 || len(query.Topics) != 1 || len(query.Topics[0]) != 1 {
+			t.Errorf("%s 的过滤条件未去重: %+v", rawQuery, query)
+		}
+	}
+
+	for _, rawQuery := range []string{
+		"",
+		"topic=" + topic,
+		"address=0x1234",
+		"address=" + address + "&topic=0x1234",
+	} {
+		if _, _, err := parse(rawQuery); err == nil {
+			t.Errorf("%q 应返回错误", rawQuery)
+		}
+	}
+}
+
+func TestStreamEventsRequireAddress(t *testing.T) {
+	s := newTestStreamServer(t, newFakeStreamSource())
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream/events", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "address") {
+		t.Errorf("未指定地址时返回 %d %s, want 400", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-eth-backend/internal/pkg/config"
+)
+
+// DefaultSize 未配置时进程内LRU缓存的最大条目数
+const DefaultSize = 10000
+
+// Backend 缓存存储后端
+type Backend interface {
+	// Get 读取缓存，不存在或已过期时ok为false
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set 写入缓存，ttl为0表示永不过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Close 释放后端占用的资源
+	Close() error
+}
+
+// NewBackend 根据缓存配置创建Redis或进程内LRU后端
+func NewBackend(cfg config.CacheConfig) (Backend, error) {
+	switch cfg.Backend {
+	case config.CacheBackendRedis, "":
+		return NewRedisBackend(cfg.RedisURL)
+	case config.CacheBackendMemory:
+		size := cfg.Size
+		if size <= 0 {
+			size = DefaultSize
+		}
+		return NewMemoryBackend(size), nil
+	default:
+		return nil, fmt.Errorf("未知的缓存后端: %s", cfg.Backend)
+	}
+}
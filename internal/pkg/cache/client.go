@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// Stats 缓存命中统计
+type Stats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// Client eth.Reader 的缓存装饰器
+// 节点 finalized 标签及之前的区块、交易和收据永久缓存，之后的不缓存；
+// 最新区块、余额、nonce和gas价格缓存ttl时长，ttl为0时不缓存
+type Client struct {
+	reader  eth.Reader
+	backend Backend
+	prefix  string
+	ttl     time.Duration
+
+	finalized atomic.Uint64 // 已知的最终确认区块号，只增不减
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+var _ eth.Reader = (*Client)(nil)
+
+// Options 缓存装饰器参数
+type Options struct {
+	// Network 网络名称，作为缓存键前缀，避免多个网络共用Redis时冲突
+	Network string
+	// TTL 最新区块、余额、nonce、gas价格和最终确认区块号的缓存时长
+	TTL time.Duration
+}
+
+// New 创建缓存装饰器
+func New(reader eth.Reader, backend Backend, options Options) *Client {
+	return &Client{
+		reader:  reader,
+		backend: backend,
+		prefix:  fmt.Sprintf("eth:%s:", options.Network),
+		ttl:     options.TTL,
+	}
+}
+
+// Stats 返回命中和未命中次数
+func (c *Client) Stats() Stats {
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// GetLatestBlockNumber 获取最新区块号（短期缓存）
+func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	return lookupShort(ctx, c, "head", func() (uint64, error) {
+		return c.reader.GetLatestBlockNumber(ctx)
+	})
+}
+
+// GetFinalizedBlockNumber 获取最终确认区块号（短期缓存）
+func (c *Client) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	return lookupShort(ctx, c, "finalized", func() (uint64, error) {
+		return c.reader.GetFinalizedBlockNumber(ctx)
+	})
+}
+
+// GetLatestJSONBlock 获取最新区块（短期缓存）
+func (c *Client) GetLatestJSONBlock(ctx context.Context, fullTx bool) (*eth.JSONBlock, error) {
+	return lookupShort(ctx, c, blockKey("block:latest", fullTx), func() (*eth.JSONBlock, error) {
+		return c.reader.GetLatestJSONBlock(ctx, fullTx)
+	})
+}
+
+// GetJSONBlockByNumber 根据区块号获取区块（最终确认后永久缓存）
+func (c *Client) GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*eth.JSONBlock, error) {
+	return lookup(ctx, c, blockKey(fmt.Sprintf("block:number:%d", number), fullTx), func() (*eth.JSONBlock, error) {
+		return c.reader.GetJSONBlockByNumber(ctx, number, fullTx)
+	}, func(block *eth.JSONBlock) (time.Duration, bool) {
+		return 0, c.isFinalized(ctx, block.Number)
+	})
+}
+
+// GetJSONBlockByHash 根据区块哈希获取区块（最终确认后永久缓存）
+func (c *Client) GetJSONBlockByHash(ctx context.Context, hash string, fullTx bool) (*eth.JSONBlock, error) {
+	return lookup(ctx, c, blockKey("block:hash:"+strings.ToLower(hash), fullTx), func() (*eth.JSONBlock, error) {
+		return c.reader.GetJSONBlockByHash(ctx, hash, fullTx)
+	}, func(block *eth.JSONBlock) (time.Duration, bool) {
+		return 0, c.isFinalized(ctx, block.Number)
+	})
+}
+
+// blockKey 区块缓存键，包含完整交易的区块与只含交易哈希的区块分开缓存
+func blockKey(key string, fullTx bool) string {
+	if fullTx {
+		return key + ":full"
+	}
+	return key
+}
+
+// cachedTransaction 交易及其是否处于待处理状态
+type cachedTransaction struct {
+	Tx      *types.Transaction `json:"tx"`
+	Pending bool               `json:"pending"`
+}
+
+// GetTransactionByHash 根据交易哈希获取交易（所在区块最终确认后永久缓存，待处理交易不缓存）
+func (c *Client) GetTransactionByHash(ctx context.Context, hash string) (*types.Transaction, bool, error) {
+	entry, err := lookup(ctx, c, "tx:"+strings.ToLower(hash), func() (cachedTransaction, error) {
+		tx, pending, err := c.reader.GetTransactionByHash(ctx, hash)
+		return cachedTransaction{Tx: tx, Pending: pending}, err
+	}, func(entry cachedTransaction) (time.Duration, bool) {
+		if entry.Pending {
+			return 0, false
+		}
+		// 交易本身不含区块号，通过收据判断所在区块是否已最终确认；直接回源，不计入命中统计
+		receipt, err := c.reader.GetTransactionReceipt(ctx, hash)
+		return 0, err == nil && c.isFinalized(ctx, receipt.BlockNumber.Uint64())
+	})
+	return entry.Tx, entry.Pending, err
+}
+
+// GetTransactionReceipt 获取交易收据（所在区块最终确认后永久缓存）
+func (c *Client) GetTransactionReceipt(ctx context.Context, hash string) (*types.Receipt, error) {
+	return lookup(ctx, c, "receipt:"+strings.ToLower(hash), func() (*types.Receipt, error) {
+		return c.reader.GetTransactionReceipt(ctx, hash)
+	}, func(receipt *types.Receipt) (time.Duration, bool) {
+		return 0, c.isFinalized(ctx, receipt.BlockNumber.Uint64())
+	})
+}
+
+// GetBalance 获取账户余额（短期缓存）
+func (c *Client) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	return lookupShort(ctx, c, "balance:"+strings.ToLower(address), func() (*big.Int, error) {
+		return c.reader.GetBalance(ctx, address)
+	})
+}
+
+// GetNonce 获取账户nonce（短期缓存）
+func (c *Client) GetNonce(ctx context.Context, address string) (uint64, error) {
+	return lookupShort(ctx, c, "nonce:"+strings.ToLower(address), func() (uint64, error) {
+		return c.reader.GetNonce(ctx, address)
+	})
+}
+
+// GetGasPrice 获取建议gas价格（短期缓存）
+func (c *Client) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	return lookupShort(ctx, c, "gas-price", func() (*big.Int, error) {
+		return c.reader.GetGasPrice(ctx)
+	})
+}
+
+// isFinalized 区块是否不晚于节点的 finalized 区块
+// 最终确认区块号只增不减，不晚于已知值的区块无需再查询；节点不支持 finalized 标签时不永久缓存
+func (c *Client) isFinalized(ctx context.Context, number uint64) bool {
+	if number <= c.finalized.Load() {
+		return true
+	}
+
+	finalized, err := c.reader.GetFinalizedBlockNumber(ctx)
+	if err != nil {
+		return false
+	}
+	for {
+		known := c.finalized.Load()
+		if finalized <= known || c.finalized.CompareAndSwap(known, finalized) {
+			break
+		}
+	}
+	return number <= finalized
+}
+
+// lookupShort 按ttl短期缓存的lookup，ttl为0时不缓存
+func lookupShort[T any](ctx context.Context, c *Client, key string, load func() (T, error)) (T, error) {
+	return lookup(ctx, c, key, load, func(T) (time.Duration, bool) {
+		return c.ttl, c.ttl > 0
+	})
+}
+
+// lookup 读取缓存，未命中时调用load并按policy写回
+// policy 返回缓存时长（0为永久）以及是否缓存；缓存后端出错时直接回源，不影响查询
+func lookup[T any](ctx context.Context, c *Client, key string, load func() (T, error), policy func(T) (time.Duration, bool)) (T, error) {
+	key = c.prefix + key
+
+	if data, ok, err := c.backend.Get(ctx, key); err == nil && ok {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			c.hits.Add(1)
+			return value, nil
+		}
+	}
+	c.misses.Add(1)
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	if ttl, ok := policy(value); ok {
+		if data, err := json.Marshal(value); err == nil {
+			_ = c.backend.Set(ctx, key, data, ttl)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,267 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// fakeReader 记录每个方法调用次数的 eth.Reader
+type fakeReader struct {
+	head      uint64
+	finalized uint64
+	balance   *big.Int
+	calls     map[string]int
+}
+
+// newFakeReader 创建链头为head、最终确认区块落后链头3个区块的链
+func newFakeReader(head uint64) *fakeReader {
+	return &fakeReader{head: head, finalized: head - 3, balance: big.NewInt(1000), calls: map[string]int{}}
+}
+
+func (r *fakeReader) block(number uint64) *eth.JSONBlock {
+	return &eth.JSONBlock{BlockHeader: eth.BlockHeader{Number: number, Hash: fmt.Sprintf("0x%064x", number)}}
+}
+
+func (r *fakeReader) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	r.calls["head"]++
+	return r.head, nil
+}
+
+func (r *fakeReader) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	r.calls["finalized"]++
+	return r.finalized, nil
+}
+
+func (r *fakeReader) GetLatestJSONBlock(ctx context.Context, fullTx bool) (*eth.JSONBlock, error) {
+	r.calls["latest"]++
+	return r.block(r.head), nil
+}
+
+func (r *fakeReader) GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*eth.JSONBlock, error) {
+	r.calls["block"]++
+	if number > r.head {
+		return nil, fmt.Errorf("区块 %d: %w", number, eth.ErrNotFound)
+	}
+	return r.block(number), nil
+}
+
+func (r *fakeReader) GetJSONBlockByHash(ctx context.Context, hash string, fullTx bool) (*eth.JSONBlock, error) {
+	r.calls["blockByHash"]++
+	return r.block(new(big.Int).SetBytes(common.FromHex(hash)).Uint64()), nil
+}
+
+func (r *fakeReader) GetTransactionByHash(ctx context.Context, hash string) (*types.Transaction, bool, error) {
+	r.calls["tx"]++
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(1)})
+	return tx, false, nil
+}
+
+func (r *fakeReader) GetTransactionReceipt(ctx context.Context, hash string) (*types.Receipt, error) {
+	r.calls["receipt"]++
+	return &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      common.HexToHash(hash),
+		BlockNumber: big.NewInt(5),
+		Logs:        []*types.Log{},
+	}, nil
+}
+
+func (r *fakeReader) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	r.calls["balance"]++
+	return new(big.Int).Set(r.balance), nil
+}
+
+func (r *fakeReader) GetNonce(ctx context.Context, address string) (uint64, error) {
+	r.calls["nonce"]++
+	return 7, nil
+}
+
+func (r *fakeReader) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	r.calls["gasPrice"]++
+	return big.NewInt(1e9), nil
+}
+
+// newRedisClient 创建基于miniredis的缓存客户端
+func newRedisClient(t *testing.T, reader eth.Reader, ttl time.Duration) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	backend, err := NewRedisBackend("redis://" + server.Addr())
+	if err != nil {
+		t.Fatalf("创建Redis后端失败: %v", err)
+	}
+	t.Cleanup(func() { backend.Close() })
+	return New(reader, backend, Options{Network: "test", TTL: ttl}), server
+}
+
+func TestFinalizedBlocksCachedForever(t *testing.T) {
+	ctx := context.Background()
+	reader := newFakeReader(10)
+	client, server := newRedisClient(t, reader, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		block, err := client.GetJSONBlockByNumber(ctx, 7, false)
+		if err != nil || block.Number != 7 {
+			t.Fatalf("GetJSONBlockByNumber = %v, %v", block, err)
+		}
+	}
+	if reader.calls["block"] != 1 {
+		t.Errorf("已确认区块回源 %d 次, want 1", reader.calls["block"])
+	}
+	if ttl := server.TTL("eth:test:block:number:7"); ttl != 0 {
+		t.Errorf("已确认区块的ttl = %v, want 永久", ttl)
+	}
+
+	// 晚于finalized的区块每次都回源
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetJSONBlockByNumber(ctx, 9, false); err != nil {
+			t.Fatalf("GetJSONBlockByNumber失败: %v", err)
+		}
+	}
+	if reader.calls["block"] != 3 {
+		t.Errorf("未确认区块回源 %d 次, want 2", reader.calls["block"]-1)
+	}
+	if server.Exists("eth:test:block:number:9") {
+		t.Error("未确认区块不应写入缓存")
+	}
+}
+
+func TestFinalityFollowsNodeTag(t *testing.T) {
+	ctx := context.Background()
+	reader := newFakeReader(10)
+	reader.finalized = 2 // 链头之下已有8个区块，但节点只最终确认到区块2
+	client, server := newRedisClient(t, reader, time.Minute)
+
+	if _, err := client.GetJSONBlockByNumber(ctx, 7, false); err != nil {
+		t.Fatalf("GetJSONBlockByNumber失败: %v", err)
+	}
+	if server.Exists("eth:test:block:number:7") {
+		t.Error("晚于finalized的区块不应永久缓存")
+	}
+
+	// 最终确认区块号前进后不再需要查询早于它的区块
+	reader.finalized = 8
+	for _, number := range []uint64{7, 6, 5} {
+		if _, err := client.GetJSONBlockByNumber(ctx, number, false); err != nil {
+			t.Fatalf("GetJSONBlockByNumber失败: %v", err)
+		}
+		if !server.Exists(fmt.Sprintf("eth:test:block:number:%d", number)) {
+			t.Errorf("区块 %d 已最终确认，应写入缓存", number)
+		}
+	}
+	if reader.calls["finalized"] != 2 {
+		t.Errorf("查询finalized %d 次, want 2", reader.calls["finalized"])
+	}
+}
+
+func TestTransactionPolicyDoesNotCountStats(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newRedisClient(t, newFakeReader(10), time.Minute)
+
+	if _, _, err := client.GetTransactionByHash(ctx, common.HexToHash("0x01").Hex()); err != nil {
+		t.Fatalf("GetTransactionByHash失败: %v", err)
+	}
+	if stats := client.Stats(); stats.Hits != 0 || stats.Misses != 1 {
+		t.Errorf("Stats = %+v, want 0 hit / 1 miss", stats)
+	}
+}
+
+func TestReceiptsAndTransactionsCached(t *testing.T) {
+	ctx := context.Background()
+	reader := newFakeReader(10)
+	client, _ := newRedisClient(t, reader, time.Minute)
+	hash := common.HexToHash("0x01").Hex()
+
+	for i := 0; i < 2; i++ {
+		receipt, err := client.GetTransactionReceipt(ctx, hash)
+		if err != nil || receipt.BlockNumber.Uint64() != 5 || receipt.TxHash.Hex() != hash {
+			t.Fatalf("GetTransactionReceipt = %v, %v", receipt, err)
+		}
+		tx, pending, err := client.GetTransactionByHash(ctx, hash)
+		if err != nil || pending || tx.Nonce() != 1 {
+			t.Fatalf("GetTransactionByHash = %v, %v, %v", tx, pending, err)
+		}
+	}
+	// 交易首次写入缓存时另外读取一次收据判断所在区块是否已最终确认
+	if reader.calls["receipt"] != 2 || reader.calls["tx"] != 1 {
+		t.Errorf("回源次数 receipt=%d tx=%d, want 2/1", reader.calls["receipt"], reader.calls["tx"])
+	}
+}
+
+func TestShortTTLExpires(t *testing.T) {
+	ctx := context.Background()
+	reader := newFakeReader(10)
+	client, server := newRedisClient(t, reader, 12*time.Second)
+	address := "0x0000000000000000000000000000000000000001"
+
+	if _, err := client.GetBalance(ctx, address); err != nil {
+		t.Fatalf("GetBalance失败: %v", err)
+	}
+	reader.balance = big.NewInt(2000)
+
+	balance, err := client.GetBalance(ctx, address)
+	if err != nil || balance.Int64() != 1000 {
+		t.Fatalf("ttl内应返回缓存余额, got %v, %v", balance, err)
+	}
+
+	server.FastForward(13 * time.Second)
+	balance, err = client.GetBalance(ctx, address)
+	if err != nil || balance.Int64() != 2000 {
+		t.Fatalf("ttl过期后应重新查询, got %v, %v", balance, err)
+	}
+
+	if stats := client.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Stats = %+v, want 1 hit / 2 misses", stats)
+	}
+}
+
+func TestMemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	reader := newFakeReader(10)
+	client := New(reader, NewMemoryBackend(2), Options{Network: "test", TTL: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetGasPrice(ctx); err != nil {
+			t.Fatalf("GetGasPrice失败: %v", err)
+		}
+		if _, err := client.GetNonce(ctx, "0x01"); err != nil {
+			t.Fatalf("GetNonce失败: %v", err)
+		}
+	}
+	if reader.calls["gasPrice"] != 1 || reader.calls["nonce"] != 1 {
+		t.Errorf("回源次数 gasPrice=%d nonce=%d, want 1/1", reader.calls["gasPrice"], reader.calls["nonce"])
+	}
+
+	// 容量为2，写入新条目后最久未使用的gas价格被淘汰
+	if _, err := client.GetLatestJSONBlock(ctx, false); err != nil {
+		t.Fatalf("GetLatestJSONBlock失败: %v", err)
+	}
+	if _, err := client.GetGasPrice(ctx); err != nil {
+		t.Fatalf("GetGasPrice失败: %v", err)
+	}
+	if reader.calls["gasPrice"] != 2 {
+		t.Errorf("淘汰后gas价格回源 %d 次, want 2", reader.calls["gasPrice"])
+	}
+}
+
+func TestZeroTTLDisablesShortCache(t *testing.T) {
+	ctx := context.Background()
+	reader := newFakeReader(10)
+	client := New(reader, NewMemoryBackend(DefaultSize), Options{Network: "test"})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetLatestBlockNumber(ctx); err != nil {
+			t.Fatalf("GetLatestBlockNumber失败: %v", err)
+		}
+	}
+	if reader.calls["head"] != 2 {
+		t.Errorf("ttl为0时最新区块号回源 %d 次, want 2", reader.calls["head"])
+	}
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+// memoryEntry 进程内缓存条目
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // 零值表示永不过期
+}
+
+// MemoryBackend 进程内LRU缓存后端，超过容量时淘汰最久未使用的条目
+type MemoryBackend struct {
+	entries *lru.Cache[string, memoryEntry]
+}
+
+// NewMemoryBackend 创建最多保存size个条目的LRU缓存后端
+func NewMemoryBackend(size int) *MemoryBackend {
+	return &MemoryBackend{entries: lru.NewCache[string, memoryEntry](size)}
+}
+
+// Get 读取缓存，过期条目视为不存在并被移除
+func (b *MemoryBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	entry, ok := b.entries.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		b.entries.Remove(key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set 写入缓存，ttl为0表示永不过期
+func (b *MemoryBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	b.entries.Add(key, entry)
+	return nil
+}
+
+// Close 进程内缓存无需释放资源
+func (b *MemoryBackend) Close() error {
+	return nil
+}
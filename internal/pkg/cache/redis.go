@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBackend 基于Redis的缓存后端，可在多个服务实例间共享
+type RedisBackend struct {
+	client *redis.Client
+}
+
+// NewRedisBackend 根据 redis:// 地址创建Redis缓存后端
+func NewRedisBackend(redisURL string) (*RedisBackend, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("解析Redis地址失败: %w", err)
+	}
+
+	return &RedisBackend{client: redis.NewClient(options)}, nil
+}
+
+// Get 读取缓存
+func (b *RedisBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := b.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("读取Redis缓存失败: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set 写入缓存，ttl为0表示永不过期
+func (b *RedisBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := b.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("写入Redis缓存失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭Redis连接
+func (b *RedisBackend) Close() error {
+	return b.client.Close()
+}
@@ -3,6 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 	"os"
@@ -13,25 +17,90 @@ type Config struct {
 	Ethereum EthereumConfig `yaml:"ethereum"`
 	Server   ServerConfig   `yaml:"server"`
 	Logging  LoggingConfig  `yaml:"logging"`
+	Database DatabaseConfig `yaml:"database"`
+	Cache    CacheConfig    `yaml:"cache"`
 }
 
 type EthereumConfig struct {
 	Accounts AccountsConfig `yaml:"accounts"`
 	Networks NetworksConfig `yaml:"networks"`
+	Timeouts TimeoutsConfig `yaml:"timeouts"`
+	// Deployments 按网络记录合约部署结果的清单文件
+	Deployments string `yaml:"deployments"`
 }
 
+// AccountsConfig 签名账户配置
+// 推荐使用keystore文件，口令从环境变量或文件读取；
+// insecure_dev_key 为明文私钥，仅限本地开发使用
 type AccountsConfig struct {
-	TestPrivateKey string `yaml:"test_private_key"`
+	Keystore       string `yaml:"keystore"`
+	PassphraseEnv  string `yaml:"passphrase_env"`
+	PassphraseFile string `yaml:"passphrase_file"`
+	InsecureDevKey string `yaml:"insecure_dev_key"`
+	// LegacyPrivateKey 旧版配置中的明文私钥，已不再使用，配置时创建签名者会报错
+	LegacyPrivateKey string `yaml:"test_private_key"`
 }
 
-type NetworksConfig struct {
-	Mainnet NetworkConfig `yaml:"mainnet"`
-	Sepolia NetworkConfig `yaml:"sepolia"`
-}
+// NetworksConfig 按名称索引的网络配置，如 mainnet、sepolia、holesky、local
+type NetworksConfig map[string]NetworkConfig
 
 type NetworkConfig struct {
-	RPCURL  string `yaml:"rpc_url"`
-	ChainID int64  `yaml:"chain_id"`
+	RPCURLs       []string        `yaml:"rpc_urls"`
+	ChainID       int64           `yaml:"chain_id"`
+	BlockTime     string          `yaml:"block_time"`
+	Confirmations uint64          `yaml:"confirmations"`
+	ExplorerURL   string          `yaml:"explorer_url"`
+	Fees          FeeConfig       `yaml:"fees"`
+	Tracker       TrackerConfig   `yaml:"tracker"`
+	RateLimit     RateLimitConfig `yaml:"rate_limit"`
+	BatchSize     int             `yaml:"batch_size"`
+	// CounterAddress 该网络上已部署的计数器合约地址，为空时REST API不提供 /counter 接口
+	CounterAddress string `yaml:"counter_address"`
+	// Create2Deployer CREATE2部署工厂地址，为空时使用标准的确定性部署代理
+	Create2Deployer string `yaml:"create2_deployer"`
+}
+
+// RateLimitConfig 客户端限流与重试配置，对网络的每个RPC地址分别生效
+// requests_per_second 为0时不限流；max_retries 未配置时使用默认值，配置为0时不重试
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+	MaxRetries        *int    `yaml:"max_retries"`
+	MinBackoff        string  `yaml:"min_backoff"`
+	MaxBackoff        string  `yaml:"max_backoff"`
+}
+
+// TrackerConfig 卡住交易监控配置
+// 交易连续 stuck_blocks 个区块未被打包时，以提高 fee_bump_percent（不低于10%）的费用重新广播
+type TrackerConfig struct {
+	StuckBlocks    uint64 `yaml:"stuck_blocks"`
+	FeeBumpPercent int64  `yaml:"fee_bump_percent"`
+}
+
+// 交易费用模式
+const (
+	// FeeModeAuto 链支持EIP-1559（最新区块含baseFee）时使用动态费用，否则使用legacy
+	FeeModeAuto = "auto"
+	// FeeModeEIP1559 强制使用EIP-1559动态费用交易
+	FeeModeEIP1559 = "eip1559"
+	// FeeModeLegacy 强制使用legacy gasPrice交易
+	FeeModeLegacy = "legacy"
+)
+
+// FeeConfig 交易费用配置
+// EIP-1559交易的 maxFeePerGas = baseFee * max_fee_multiplier + maxPriorityFeePerGas；
+// 自动估算的gas上限为 估算值 * gas_limit_multiplier
+type FeeConfig struct {
+	Mode               string  `yaml:"mode"`
+	MaxFeeMultiplier   float64 `yaml:"max_fee_multiplier"`
+	GasLimitMultiplier float64 `yaml:"gas_limit_multiplier"`
+}
+
+// TimeoutsConfig RPC调用超时配置
+type TimeoutsConfig struct {
+	Request   string `yaml:"request"`
+	Receipt   string `yaml:"receipt"`
+	NonceIdle string `yaml:"nonce_idle"`
 }
 
 type ServerConfig struct {
@@ -39,6 +108,16 @@ type ServerConfig struct {
 	Host         string `yaml:"host"`
 	ReadTimeout  string `yaml:"read_timeout"`
 	WriteTimeout string `yaml:"write_timeout"`
+	// StreamPort 推送服务（SSE/WebSocket）的端口，为0时不启动
+	StreamPort int `yaml:"stream_port"`
+	// StreamABIFiles 推送合约事件时用于解码日志的ABI文件
+	StreamABIFiles []string `yaml:"stream_abi_files"`
+	// CounterABIFile 计数器合约的ABI文件，配合网络的 counter_address 使用
+	CounterABIFile string `yaml:"counter_abi_file"`
+	// CounterWrites 开启 /counter 的写接口，默认关闭；开启后请求需携带 Authorization: Bearer <token>
+	CounterWrites bool `yaml:"counter_writes"`
+	// CounterTokenEnv 保存写接口访问令牌的环境变量名
+	CounterTokenEnv string `yaml:"counter_token_env"`
 }
 
 type LoggingConfig struct {
@@ -47,6 +126,33 @@ type LoggingConfig struct {
 	FilePath string `yaml:"file_path"`
 }
 
+// DatabaseConfig PostgreSQL数据库配置，供区块索引器使用
+type DatabaseConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Name     string `yaml:"name"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	SSLMode  string `yaml:"sslmode"`
+}
+
+// 缓存后端
+const (
+	CacheBackendRedis  = "redis"
+	CacheBackendMemory = "memory"
+)
+
+// CacheConfig 查询结果缓存配置
+// 已最终确认的区块、交易和收据永久缓存，最新区块、余额、nonce和gas价格按ttl（秒）缓存
+type CacheConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Backend  string `yaml:"backend"`
+	RedisURL string `yaml:"redis_url"`
+	TTL      int    `yaml:"ttl"`
+	Size     int    `yaml:"size"`
+}
+
 // LoadConfig 从文件加载配置
 func LoadConfig(configPath string) (*Config, error) {
 	data, err := os.ReadFile(configPath)
@@ -63,14 +169,189 @@ func LoadConfig(configPath string) (*Config, error) {
 	return &config, nil
 }
 
-// GetTestPrivateKey 获取测试网络私钥
-func (c *Config) GetTestPrivateKey() string {
-	return c.Ethereum.Accounts.TestPrivateKey
+// GetAccountsConfig 获取签名账户配置
+func (c *Config) GetAccountsConfig() AccountsConfig {
+	return c.Ethereum.Accounts
+}
+
+// HasSigner 是否配置了签名账户，旧版明文私钥也算在内，以便创建签名者时提示迁移
+func (a AccountsConfig) HasSigner() bool {
+	return a.Keystore != "" || a.InsecureDevKey != "" || a.LegacyPrivateKey != ""
+}
+
+// GetPassphrase 获取keystore口令，优先读取环境变量，其次读取口令文件
+func (a AccountsConfig) GetPassphrase() (string, error) {
+	if a.PassphraseEnv != "" {
+		if passphrase, ok := os.LookupEnv(a.PassphraseEnv); ok {
+			return passphrase, nil
+		}
+	}
+
+	if a.PassphraseFile != "" {
+		data, err := os.ReadFile(a.PassphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("读取口令文件失败: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	return "", fmt.Errorf("未找到keystore口令: 请设置环境变量 %s 或配置 passphrase_file", a.PassphraseEnv)
+}
+
+// GetDeploymentsFile 获取部署清单文件路径，未配置时为当前目录下的 deployments.json
+func (c *Config) GetDeploymentsFile() string {
+	if c.Ethereum.Deployments == "" {
+		return "deployments.json"
+	}
+	return c.Ethereum.Deployments
+}
+
+// GetNetwork 根据名称获取网络配置
+func (c *Config) GetNetwork(name string) (NetworkConfig, error) {
+	network, ok := c.Ethereum.Networks[name]
+	if !ok {
+		return NetworkConfig{}, fmt.Errorf("未找到网络配置: %s", name)
+	}
+	return network, nil
+}
+
+// GetNetworkNames 获取已配置的网络名称（按字母排序）
+func (c *Config) GetNetworkNames() []string {
+	names := make([]string, 0, len(c.Ethereum.Networks))
+	for name := range c.Ethereum.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // GetSepoliaConfig 获取Sepolia网络配置
 func (c *Config) GetSepoliaConfig() NetworkConfig {
-	return c.Ethereum.Networks.Sepolia
+	return c.Ethereum.Networks["sepolia"]
+}
+
+// RPCURL 返回首选RPC地址，未配置时返回空字符串
+func (n NetworkConfig) RPCURL() string {
+	if len(n.RPCURLs) == 0 {
+		return ""
+	}
+	return n.RPCURLs[0]
+}
+
+// GetBlockTime 解析出块间隔，未配置时返回0
+func (n NetworkConfig) GetBlockTime() (time.Duration, error) {
+	return parseDuration("block_time", n.BlockTime)
+}
+
+// GetMinBackoff 解析第一次重试前的等待时间，未配置时返回0
+func (r RateLimitConfig) GetMinBackoff() (time.Duration, error) {
+	return parseDuration("min_backoff", r.MinBackoff)
+}
+
+// GetMaxBackoff 解析重试等待时间上限，未配置时返回0
+func (r RateLimitConfig) GetMaxBackoff() (time.Duration, error) {
+	return parseDuration("max_backoff", r.MaxBackoff)
+}
+
+// GetTimeoutsConfig 获取RPC调用超时配置
+func (c *Config) GetTimeoutsConfig() TimeoutsConfig {
+	return c.Ethereum.Timeouts
+}
+
+// GetRequestTimeout 解析单次RPC调用超时，未配置时返回0
+func (t TimeoutsConfig) GetRequestTimeout() (time.Duration, error) {
+	return parseDuration("request", t.Request)
+}
+
+// GetReceiptTimeout 解析等待交易收据的超时，未配置时返回0
+func (t TimeoutsConfig) GetReceiptTimeout() (time.Duration, error) {
+	return parseDuration("receipt", t.Receipt)
+}
+
+// GetNonceIdleTimeout 解析本地nonce状态的空闲超时，超时后从节点重新同步，未配置时返回0
+func (t TimeoutsConfig) GetNonceIdleTimeout() (time.Duration, error) {
+	return parseDuration("nonce_idle", t.NonceIdle)
+}
+
+// GetDatabaseConfig 获取数据库配置
+func (c *Config) GetDatabaseConfig() DatabaseConfig {
+	return c.Database
+}
+
+// DSN 返回PostgreSQL连接串，sslmode未配置时为disable
+func (d DatabaseConfig) DSN() string {
+	sslMode := d.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(d.User, d.Password),
+		Host:     fmt.Sprintf("%s:%d", d.Host, d.Port),
+		Path:     "/" + d.Name,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	return dsn.String()
+}
+
+// GetCacheConfig 获取缓存配置
+func (c *Config) GetCacheConfig() CacheConfig {
+	return c.Cache
+}
+
+// GetTTL 返回短期缓存的有效期，未配置时返回0
+func (c CacheConfig) GetTTL() time.Duration {
+	return time.Duration(c.TTL) * time.Second
+}
+
+// GetServerConfig 获取HTTP服务器配置
+func (c *Config) GetServerConfig() ServerConfig {
+	return c.Server
+}
+
+// Address 返回服务器监听地址 host:port
+func (s ServerConfig) Address() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// StreamAddress 返回推送服务监听地址
+func (s ServerConfig) StreamAddress() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.StreamPort)
+}
+
+// GetCounterToken 从环境变量读取计数器写接口的访问令牌，未设置或为空时返回错误
+func (s ServerConfig) GetCounterToken() (string, error) {
+	if s.CounterTokenEnv == "" {
+		return "", fmt.Errorf("开启 counter_writes 时必须配置 counter_token_env")
+	}
+	token := os.Getenv(s.CounterTokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("未找到计数器写接口的访问令牌: 请设置环境变量 %s", s.CounterTokenEnv)
+	}
+	return token, nil
+}
+
+// GetReadTimeout 解析读超时，未配置时返回0（不限制）
+func (s ServerConfig) GetReadTimeout() (time.Duration, error) {
+	return parseDuration("read_timeout", s.ReadTimeout)
+}
+
+// GetWriteTimeout 解析写超时，未配置时返回0（不限制）
+func (s ServerConfig) GetWriteTimeout() (time.Duration, error) {
+	return parseDuration("write_timeout", s.WriteTimeout)
+}
+
+// parseDuration 解析形如 "30s" 的时间配置项
+func parseDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("解析配置项 %s 失败: %v", name, err)
+	}
+	return d, nil
 }
 
 // LoadConfigOrExit 加载配置，如果失败则退出程序
@@ -80,9 +361,9 @@ func LoadConfigOrExit(configPath string) *Config {
 		log.Fatalf("❌ 配置加载失败: %v", err)
 	}
 	
-	// 验证测试私钥是否存在
-	if config.GetTestPrivateKey() == "" {
-		log.Fatal("❌ 配置文件中未找到测试私钥")
+	// 验证签名账户是否已配置
+	if !config.GetAccountsConfig().HasSigner() {
+		log.Fatal("❌ 配置文件中未找到签名账户（keystore 或 insecure_dev_key）")
 	}
 	
 	return config
@@ -0,0 +1,255 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// ConvertArgs 按ABI参数类型转换调用参数，使同一份参数既可以来自Go代码也可以来自JSON请求或命令行
+// 已是ABI对应Go类型的参数原样使用；其余按类型转换：
+// 整数接受十进制或0x十六进制字符串、JSON数字和Go整数类型；address、bytes、bytesN接受0x十六进制字符串；
+// bool接受 true/false 字符串；数组和切片接受JSON数组或其字符串形式；tuple接受以字段名为键的JSON对象
+func ConvertArgs(inputs abi.Arguments, args []interface{}) ([]interface{}, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("参数数量不匹配: 需要 %d 个, 提供了 %d 个", len(inputs), len(args))
+	}
+
+	converted := make([]interface{}, len(args))
+	for i, input := range inputs {
+		value, err := convertValue(input.Type, args[i])
+		if err != nil {
+			name := input.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			return nil, fmt.Errorf("参数 %s (%s) 无效: %w", name, input.Type, err)
+		}
+		converted[i] = value.Interface()
+	}
+	return converted, nil
+}
+
+// convertValue 将v转换为ABI类型对应的Go类型
+func convertValue(typ abi.Type, v interface{}) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Value{}, errors.New("参数为空")
+	}
+	goType := typ.GetType()
+	// 超过64位的整数对应 *big.Int，类型相同也要检查符号和位数，否则负数会被打包成补码
+	if rv := reflect.ValueOf(v); rv.Type() == goType && typ.T != abi.IntTy && typ.T != abi.UintTy {
+		return rv, nil
+	}
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := toBigInt(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return intValue(typ, n)
+	case abi.BoolTy:
+		s, ok := v.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("无法将 %T 转换为bool", v)
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("无效的bool值: %s", s)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.AddressTy:
+		s, ok := v.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("无效的地址: %v", v)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case abi.StringTy:
+		return reflect.Value{}, fmt.Errorf("无法将 %T 转换为string", v)
+	case abi.BytesTy:
+		b, err := toBytes(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+	case abi.FixedBytesTy:
+		b, err := toBytes(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != typ.Size {
+			return reflect.Value{}, fmt.Errorf("长度应为 %d 字节, 实际为 %d 字节", typ.Size, len(b))
+		}
+		array := reflect.New(goType).Elem()
+		reflect.Copy(array, reflect.ValueOf(b))
+		return array, nil
+	case abi.SliceTy, abi.ArrayTy:
+		return listValue(typ, v)
+	case abi.TupleTy:
+		return tupleValue(typ, v)
+	default:
+		return reflect.Value{}, fmt.Errorf("不支持的参数类型 %s", typ)
+	}
+}
+
+// intValue 检查整数范围并转换为ABI整数类型对应的Go类型（不超过64位时为对应的Go整数类型，否则为 *big.Int）
+func intValue(typ abi.Type, n *big.Int) (reflect.Value, error) {
+	if typ.T == abi.UintTy {
+		if n.Sign() < 0 || n.BitLen() > typ.Size {
+			return reflect.Value{}, fmt.Errorf("%s 超出 uint%d 范围", n, typ.Size)
+		}
+	} else {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(typ.Size-1))
+		if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return reflect.Value{}, fmt.Errorf("%s 超出 int%d 范围", n, typ.Size)
+		}
+	}
+
+	goType := typ.GetType()
+	switch {
+	case goType == bigIntType:
+		return reflect.ValueOf(n), nil
+	case typ.T == abi.UintTy:
+		return reflect.ValueOf(n.Uint64()).Convert(goType), nil
+	default:
+		return reflect.ValueOf(n.Int64()).Convert(goType), nil
+	}
+}
+
+// toBigInt 将字符串、JSON数字或Go整数转换为 *big.Int
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		return n, nil
+	case big.Int:
+		return &n, nil
+	case string:
+		parsed, ok := new(big.Int).SetString(strings.TrimSpace(n), 0)
+		if !ok {
+			return nil, fmt.Errorf("无效的整数: %s", n)
+		}
+		return parsed, nil
+	case json.Number:
+		parsed, ok := new(big.Int).SetString(n.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("无效的整数: %s", n)
+		}
+		return parsed, nil
+	case float64:
+		// JSON数字解码为float64，超过2^53的整数会丢失精度，应使用字符串传递
+		if n != math.Trunc(n) || math.IsInf(n, 0) {
+			return nil, fmt.Errorf("无效的整数: %v", n)
+		}
+		parsed, _ := big.NewFloat(n).Int(nil)
+		return parsed, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("无法将 %T 转换为整数", v)
+}
+
+// toBytes 将0x十六进制字符串或字节切片转换为 []byte
+func toBytes(v interface{}) ([]byte, error) {
+	switch b := v.(type) {
+	case string:
+		decoded, err := hexutil.Decode(strings.TrimSpace(b))
+		if err != nil {
+			return nil, fmt.Errorf("无效的十六进制数据 %s: %w", b, err)
+		}
+		return decoded, nil
+	case []byte:
+		return b, nil
+	case hexutil.Bytes:
+		return b, nil
+	}
+	return nil, fmt.Errorf("无法将 %T 转换为bytes", v)
+}
+
+// listValue 将JSON数组（或其字符串形式、任意Go切片）转换为ABI数组或切片
+func listValue(typ abi.Type, v interface{}) (reflect.Value, error) {
+	if s, ok := v.(string); ok {
+		var items []interface{}
+		if err := decodeJSON(s, &items); err != nil {
+			return reflect.Value{}, fmt.Errorf("无效的数组: %w", err)
+		}
+		v = items
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return reflect.Value{}, fmt.Errorf("无法将 %T 转换为数组", v)
+	}
+	if typ.T == abi.ArrayTy && rv.Len() != typ.Size {
+		return reflect.Value{}, fmt.Errorf("数组长度应为 %d, 实际为 %d", typ.Size, rv.Len())
+	}
+
+	var list reflect.Value
+	if typ.T == abi.SliceTy {
+		list = reflect.MakeSlice(typ.GetType(), rv.Len(), rv.Len())
+	} else {
+		list = reflect.New(typ.GetType()).Elem()
+	}
+	for i := 0; i < rv.Len(); i++ {
+		elem, err := convertValue(*typ.Elem, rv.Index(i).Interface())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("第 %d 个元素: %w", i, err)
+		}
+		list.Index(i).Set(elem)
+	}
+	return list, nil
+}
+
+// tupleValue 将以字段名为键的JSON对象（或其字符串形式）转换为ABI tuple对应的结构体
+func tupleValue(typ abi.Type, v interface{}) (reflect.Value, error) {
+	if s, ok := v.(string); ok {
+		var fields map[string]interface{}
+		if err := decodeJSON(s, &fields); err != nil {
+			return reflect.Value{}, fmt.Errorf("无效的tuple: %w", err)
+		}
+		v = fields
+	}
+
+	fields, ok := v.(map[string]interface{})
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("无法将 %T 转换为tuple", v)
+	}
+
+	tuple := reflect.New(typ.GetType()).Elem()
+	for i, elem := range typ.TupleElems {
+		name := typ.TupleRawNames[i]
+		raw, ok := fields[name]
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("缺少字段 %s", name)
+		}
+		value, err := convertValue(*elem, raw)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("字段 %s: %w", name, err)
+		}
+		tuple.Field(i).Set(value)
+	}
+	return tuple, nil
+}
+
+// decodeJSON 解码JSON字符串，数字保留为 json.Number 以免大整数丢失精度
+func decodeJSON(s string, v interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,150 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mustType 解析ABI类型，tuple的字段由components给出
+func mustType(t *testing.T, name string, components ...abi.ArgumentMarshaling) abi.Type {
+	t.Helper()
+	typ, err := abi.NewType(name, "", components)
+	if err != nil {
+		t.Fatalf("解析类型 %s 失败: %v", name, err)
+	}
+	return typ
+}
+
+// pow2 返回 2^n
+func pow2(n uint) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), n)
+}
+
+func TestConvertValue(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(pow2(256), big.NewInt(1))
+	minInt256 := new(big.Int).Neg(pow2(255))
+	order := []abi.ArgumentMarshaling{{Name: "owner", Type: "address"}, {Name: "amount", Type: "uint256"}}
+	owner := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+
+	tests := []struct {
+		name       string
+		typ        string
+		components []abi.ArgumentMarshaling
+		arg        interface{}
+		want       interface{} // nil表示应返回错误
+	}{
+		// 整数范围
+		{"uint8上限", "uint8", nil, "255", uint8(255)},
+		{"uint8溢出", "uint8", nil, "256", nil},
+		{"uint8负数", "uint8", nil, "-1", nil},
+		{"uint8 JSON数字", "uint8", nil, float64(3), uint8(3)},
+		{"uint8小数", "uint8", nil, 3.5, nil},
+		{"int8下限", "int8", nil, "-128", int8(-128)},
+		{"int8溢出", "int8", nil, json.Number("128"), nil},
+		{"int8下溢", "int8", nil, "-129", nil},
+		{"uint64十六进制", "uint64", nil, "0xffffffffffffffff", ^uint64(0)},
+		{"uint64由int转换", "uint64", nil, 42, uint64(42)},
+		{"uint256上限", "uint256", nil, maxUint256, maxUint256},
+		{"uint256溢出", "uint256", nil, pow2(256), nil},
+		{"uint256负数big.Int", "uint256", nil, big.NewInt(-1), nil},
+		{"uint256十六进制", "uint256", nil, "0x10", big.NewInt(16)},
+		{"int256下限", "int256", nil, minInt256, minInt256},
+		{"int256溢出big.Int", "int256", nil, pow2(255), nil},
+		{"int24溢出big.Int", "int24", nil, pow2(23), nil},
+		{"int24下限", "int24", nil, "-8388608", big.NewInt(-8388608)},
+		{"无效整数", "uint256", nil, "abc", nil},
+
+		// bytes、address、bool、string
+		{"bytes4", "bytes4", nil, "0x01020304", [4]byte{1, 2, 3, 4}},
+		{"bytes4由切片转换", "bytes4", nil, []byte{1, 2, 3, 4}, [4]byte{1, 2, 3, 4}},
+		{"bytes4长度不足", "bytes4", nil, "0x010203", nil},
+		{"bytes4长度过长", "bytes4", nil, "0x0102030405", nil},
+		{"bytes32原样使用", "bytes32", nil, [32]byte{1}, [32]byte{1}},
+		{"bytes空", "bytes", nil, "0x", []byte{}},
+		{"bytes无效十六进制", "bytes", nil, "0xzz", nil},
+		{"address", "address", nil, owner.Hex(), owner},
+		{"address无效", "address", nil, "0x1234", nil},
+		{"bool", "bool", nil, "true", true},
+		{"bool无效", "bool", nil, "yes", nil},
+		{"string", "string", nil, "hello", "hello"},
+		{"string不接受数字", "string", nil, 1, nil},
+
+		// 数组和切片
+		{"定长数组JSON字符串", "uint256[2]", nil, `[1, "0x2"]`, [2]*big.Int{big.NewInt(1), big.NewInt(2)}},
+		{"定长数组长度不符", "uint256[2]", nil, `[1]`, nil},
+		{"切片", "uint8[]", nil, []interface{}{"1", float64(2)}, []uint8{1, 2}},
+		{"切片元素溢出", "uint8[]", nil, []interface{}{"1", "300"}, nil},
+		{"嵌套切片JSON字符串", "uint16[][]", nil, `[[1], [2, 3]]`, [][]uint16{{1}, {2, 3}}},
+		{"大整数JSON字符串不丢失精度", "uint256[]", nil, `[115792089237316195423570985008687907853269984665640564039457584007913129639935]`, []*big.Int{maxUint256}},
+		{"数组不接受整数", "uint8[]", nil, 1, nil},
+
+		// tuple
+		{"tuple按字段名", "tuple", order, map[string]interface{}{"owner": owner.Hex(), "amount": "100"},
+			struct {
+				Owner  common.Address `json:"owner"`
+				Amount *big.Int       `json:"amount"`
+			}{owner, big.NewInt(100)}},
+		{"tuple JSON字符串", "tuple", order, `{"owner": "` + owner.Hex() + `", "amount": 7}`,
+			struct {
+				Owner  common.Address `json:"owner"`
+				Amount *big.Int       `json:"amount"`
+			}{owner, big.NewInt(7)}},
+		{"tuple缺少字段", "tuple", order, map[string]interface{}{"owner": owner.Hex()}, nil},
+		{"tuple字段无效", "tuple", order, map[string]interface{}{"owner": owner.Hex(), "amount": "-1"}, nil},
+		{"tuple不接受数组", "tuple", order, []interface{}{owner.Hex(), "1"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := convertValue(mustType(t, tt.typ, tt.components...), tt.arg)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("convertValue(%v) = %v, want 错误", tt.arg, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("convertValue(%v) 失败: %v", tt.arg, err)
+			}
+			// *big.Int 按数值比较
+			if got.Type() != reflect.TypeOf(tt.want) || fmt.Sprint(got.Interface()) != fmt.Sprint(tt.want) {
+				t.Errorf("convertValue(%v) = %T %v, want %T %v", tt.arg, got.Interface(), got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertArgs(t *testing.T) {
+	inputs := abi.Arguments{
+		{Name: "to", Type: mustType(t, "address")},
+		{Name: "", Type: mustType(t, "uint256")},
+	}
+
+	args, err := ConvertArgs(inputs, []interface{}{"0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20", "1000"})
+	if err != nil {
+		t.Fatalf("转换参数失败: %v", err)
+	}
+	if _, err := inputs.Pack(args...); err != nil {
+		t.Errorf("转换后的参数无法打包: %v", err)
+	}
+
+	if _, err := ConvertArgs(inputs, []interface{}{"0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20"}); err == nil {
+		t.Error("参数数量不匹配时应返回错误")
+	}
+
+	// 错误信息指出参数名，未命名参数使用序号
+	_, err = ConvertArgs(inputs, []interface{}{"0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20", big.NewInt(-1)})
+	if err == nil || !strings.Contains(err.Error(), "#1") || !strings.Contains(err.Error(), "uint256") {
+		t.Errorf("err = %v, want 指出参数 #1 (uint256)", err)
+	}
+	_, err = ConvertArgs(inputs, []interface{}{"0x12", "1"})
+	if err == nil || !strings.Contains(err.Error(), "参数 to") {
+		t.Errorf("err = %v, want 指出参数 to", err)
+	}
+}
@@ -0,0 +1,229 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultBatchSize 单个JSON-RPC批量请求默认包含的调用数
+const DefaultBatchSize = 100
+
+// rpcBlockBody eth_getBlockByNumber 返回的区块体（含完整交易）
+type rpcBlockBody struct {
+	Transactions []*types.Transaction `json:"transactions"`
+	Withdrawals  []*types.Withdrawal  `json:"withdrawals,omitempty"`
+}
+
+// GetBlocksRange 通过批量请求获取 [from, to] 范围内包含完整交易的区块，按区块号排序
+// 不额外查询叔块头，返回区块的 Uncles 为空
+func (c *Client) GetBlocksRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	if from > to {
+		return nil, fmt.Errorf("区块范围无效: %d > %d", from, to)
+	}
+	if c.rpc == nil {
+		return c.getBlocksOneByOne(ctx, from, to)
+	}
+
+	raws := make([]json.RawMessage, to-from+1)
+	elems := make([]rpc.BatchElem, len(raws))
+	for i := range elems {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(from + uint64(i)), true},
+			Result: &raws[i],
+		}
+	}
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, wrapError(err, "批量获取区块 %d-%d 失败", from, to)
+	}
+
+	blocks := make([]*types.Block, len(elems))
+	for i, elem := range elems {
+		number := from + uint64(i)
+		if elem.Error != nil {
+			return nil, wrapError(elem.Error, "获取区块 %d 失败", number)
+		}
+
+		block, err := decodeBlock(raws[i])
+		if err != nil {
+			return nil, wrapError(err, "获取区块 %d 失败", number)
+		}
+		blocks[i] = block
+	}
+
+	return blocks, nil
+}
+
+// GetReceipts 通过批量请求获取多笔交易的收据，顺序与hashes一致
+func (c *Client) GetReceipts(ctx context.Context, hashes []string) ([]*types.Receipt, error) {
+	if c.rpc == nil {
+		return c.getReceiptsOneByOne(ctx, hashes)
+	}
+
+	receipts := make([]*types.Receipt, len(hashes))
+	elems := make([]rpc.BatchElem, len(hashes))
+	for i, hash := range hashes {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{common.HexToHash(hash)},
+			Result: &receipts[i],
+		}
+	}
+	if err := c.batchCall(ctx, elems); err != nil {
+		return nil, wrapError(err, "批量获取交易收据失败")
+	}
+
+	for i, elem := range elems {
+		err := elem.Error
+		if err == nil && receipts[i] == nil {
+			err = ethereum.NotFound
+		}
+		if err != nil {
+			return nil, wrapError(err, "获取交易收据 %s 失败", hashes[i])
+		}
+	}
+
+	return receipts, nil
+}
+
+// GetBlockReceipts 获取区块中全部交易的收据，顺序与区块内交易一致
+// 节点支持 eth_getBlockReceipts 时一次请求取回整个区块的收据，否则按交易哈希批量查询
+func (c *Client) GetBlockReceipts(ctx context.Context, block *types.Block) ([]*types.Receipt, error) {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return []*types.Receipt{}, nil
+	}
+
+	if c.rpc != nil && !c.noBlockReceipts.Load() {
+		receipts, err := c.blockReceipts(ctx, block.Hash())
+		switch {
+		case err == nil && len(receipts) == len(txs):
+			return receipts, nil
+		case err == nil:
+			return nil, fmt.Errorf("区块 %d 返回 %d 条收据，应为 %d 条", block.NumberU64(), len(receipts), len(txs))
+		case !isMethodNotFound(err):
+			return nil, wrapError(err, "获取区块 %d 的收据失败", block.NumberU64())
+		}
+		// 记住节点不支持该方法，之后直接按交易哈希查询
+		c.noBlockReceipts.Store(true)
+	}
+
+	hashes := make([]string, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash().Hex()
+	}
+	return c.GetReceipts(ctx, hashes)
+}
+
+// blockReceipts 按区块哈希调用 eth_getBlockReceipts，链重组后不会返回其他区块的收据
+func (c *Client) blockReceipts(ctx context.Context, hash common.Hash) ([]*types.Receipt, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	var receipts []*types.Receipt
+	err := c.rpc.CallContext(ctx, &receipts, "eth_getBlockReceipts", rpc.BlockNumberOrHashWithHash(hash, false))
+	return receipts, err
+}
+
+// getBlocksOneByOne 没有JSON-RPC连接（直接注入的后端）时逐个获取区块
+func (c *Client) getBlocksOneByOne(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	blocks := make([]*types.Block, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		block, err := c.GetRawBlockByNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// getReceiptsOneByOne 没有JSON-RPC连接（直接注入的后端）时逐个获取收据
+func (c *Client) getReceiptsOneByOne(ctx context.Context, hashes []string) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(hashes))
+	for i, hash := range hashes {
+		receipt, err := c.GetTransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		receipts[i] = receipt
+	}
+	return receipts, nil
+}
+
+// batchCall 按客户端的批量大小分批发送调用
+// 返回的error表示整批请求失败，单个调用的错误见各 BatchElem.Error
+func (c *Client) batchCall(ctx context.Context, elems []rpc.BatchElem) error {
+	for start := 0; start < len(elems); start += c.batchSize {
+		end := min(start+c.batchSize, len(elems))
+		if err := c.sendBatch(ctx, elems[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendBatch 发送一个批量请求，被节点以批量过大拒绝时对半拆分后分别重试
+func (c *Client) sendBatch(ctx context.Context, elems []rpc.BatchElem) error {
+	callCtx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	err := c.rpc.BatchCallContext(callCtx, elems)
+	cancel()
+
+	if len(elems) == 1 || !batchRejected(elems, err) {
+		return err
+	}
+
+	for i := range elems {
+		elems[i].Error = nil
+	}
+	half := len(elems) / 2
+	if err := c.sendBatch(ctx, elems[:half]); err != nil {
+		return err
+	}
+	return c.sendBatch(ctx, elems[half:])
+}
+
+// batchRejected 判断批量请求是否因超出节点限制被整体或部分拒绝
+func batchRejected(elems []rpc.BatchElem, err error) bool {
+	if err != nil {
+		return isBatchLimit(err)
+	}
+	for _, elem := range elems {
+		if elem.Error != nil && isBatchLimit(elem.Error) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeBlock 解码 eth_getBlockByNumber 返回的完整区块，区块不存在时返回 ethereum.NotFound
+func decodeBlock(raw json.RawMessage) (*types.Block, error) {
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+
+	var body rpcBlockBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+	if head.TxHash != types.EmptyTxsHash && len(body.Transactions) == 0 {
+		return nil, errors.New("节点返回的区块缺少交易列表")
+	}
+
+	return types.NewBlockWithHeader(head).WithBody(types.Body{
+		Transactions: body.Transactions,
+		Withdrawals:  body.Withdrawals,
+	}), nil
+}
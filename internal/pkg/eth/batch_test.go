@@ -0,0 +1,141 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// newBatchClient 创建直接连接假节点的客户端，批量请求不经过节点池
+func newBatchClient(t *testing.T, node *fakeNode, batchSize int) *Client {
+	t.Helper()
+	client, err := newClientWithPolicy(node.URL, RetryPolicy{})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(client.Close)
+	client.batchSize = batchSize
+	return client
+}
+
+func TestGetBlocksRangeSplitsRejectedBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		reject string
+	}{
+		{"geth只对第一个调用返回错误", "geth"},
+		{"缺少响应返回ErrMissingBatchResponse", "truncate"},
+		{"HTTP 413", "413"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newFakeNode(t, &fakeMempool{txs: make(map[string]bool)}, 100)
+			node.set(func(n *fakeNode) {
+				n.maxBatch = 4
+				n.batchReject = tt.reject
+			})
+			client := newBatchClient(t, node, 10)
+
+			blocks, err := client.GetBlocksRange(context.Background(), 10, 19)
+			if err != nil {
+				t.Fatalf("批量获取区块失败: %v", err)
+			}
+			for i, block := range blocks {
+				if block.NumberU64() != uint64(10+i) {
+					t.Errorf("第 %d 个区块 = %d, want %d", i, block.NumberU64(), 10+i)
+				}
+			}
+
+			// 10个调用被拒绝后拆成5+5，仍被拒绝再拆成2+3
+			if got := fmt.Sprint(node.batchSizes()); got != "[10 5 2 3 5 2 3]" {
+				t.Errorf("批量请求大小 = %s, want [10 5 2 3 5 2 3]", got)
+			}
+		})
+	}
+}
+
+func TestGetBlocksRangeDoesNotSplitOtherErrors(t *testing.T) {
+	node := newFakeNode(t, &fakeMempool{txs: make(map[string]bool)}, 100)
+	client := newBatchClient(t, node, 10)
+
+	// 超出链头的区块不存在，不是批量过大，不应拆分重试
+	_, err := client.GetBlocksRange(context.Background(), 95, 104)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if got := fmt.Sprint(node.batchSizes()); got != "[10]" {
+		t.Errorf("批量请求大小 = %s, want [10]", got)
+	}
+}
+
+// blockWithTxs 构造包含3笔交易的区块
+func blockWithTxs(t *testing.T) *types.Block {
+	t.Helper()
+	txs := []*types.Transaction{signedTestTx(t), signedTestTx(t), signedTestTx(t)}
+	return types.NewBlock(&types.Header{Number: big.NewInt(1)}, &types.Body{Transactions: txs}, nil, trie.NewStackTrie(nil))
+}
+
+// checkReceipts 检查收据与区块内交易一一对应
+func checkReceipts(t *testing.T, block *types.Block, receipts []*types.Receipt) {
+	t.Helper()
+	if len(receipts) != len(block.Transactions()) {
+		t.Fatalf("收据数量 = %d, want %d", len(receipts), len(block.Transactions()))
+	}
+	for i, tx := range block.Transactions() {
+		if receipts[i].TxHash != tx.Hash() {
+			t.Errorf("第 %d 条收据属于交易 %s, want %s", i, receipts[i].TxHash, tx.Hash())
+		}
+	}
+}
+
+func TestGetBlockReceiptsByBlock(t *testing.T) {
+	node := newFakeNode(t, &fakeMempool{txs: make(map[string]bool)}, 100)
+	client := newBatchClient(t, node, DefaultBatchSize)
+
+	block := blockWithTxs(t)
+	hashes := make([]common.Hash, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		hashes = append(hashes, tx.Hash())
+	}
+	node.set(func(n *fakeNode) { n.blockTxs = map[common.Hash][]common.Hash{block.Hash(): hashes} })
+
+	receipts, err := client.GetBlockReceipts(context.Background(), block)
+	if err != nil {
+		t.Fatalf("获取收据失败: %v", err)
+	}
+	checkReceipts(t, block, receipts)
+	if got := node.count("eth_getTransactionReceipt"); got != 0 {
+		t.Errorf("支持 eth_getBlockReceipts 时按哈希查询了 %d 次", got)
+	}
+}
+
+func TestGetBlockReceiptsFallsBackToHashes(t *testing.T) {
+	node := newFakeNode(t, &fakeMempool{txs: make(map[string]bool)}, 100)
+	client := newBatchClient(t, node, DefaultBatchSize)
+	block := blockWithTxs(t)
+
+	for i := 0; i < 2; i++ {
+		receipts, err := client.GetBlockReceipts(context.Background(), block)
+		if err != nil {
+			t.Fatalf("第%d次获取收据失败: %v", i, err)
+		}
+		checkReceipts(t, block, receipts)
+	}
+
+	// 节点返回方法不存在后记住结果，第二次直接按哈希批量查询
+	if got := node.count("eth_getBlockReceipts"); got != 1 {
+		t.Errorf("eth_getBlockReceipts 调用 %d 次, want 1", got)
+	}
+	if got := node.count("eth_getTransactionReceipt"); got != 6 {
+		t.Errorf("eth_getTransactionReceipt 调用 %d 次, want 6", got)
+	}
+	if got := fmt.Sprint(node.batchSizes()); got != "[3 3]" {
+		t.Errorf("批量请求大小 = %s, want [3 3]", got)
+	}
+}
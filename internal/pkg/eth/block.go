@@ -1,58 +1,195 @@
 package eth
 
 import (
-	"fmt"
+	"context"
+	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// JSONBlock 用于JSON序列化的区块信息结构体
+// JSONBlock 区块信息结构体：完整区块头加区块体
+// json.Marshal 保留区块头的全部字段，可通过 BlockHeader.ToHeader 还原出哈希一致的原生区块头
 type JSONBlock struct {
-	Number          uint64        `json:"number"`
-	Hash            string        `json:"hash"`
-	Timestamp       time.Time     `json:"timestamp"`
-	ParentHash      string        `json:"parentHash"`
-	Difficulty      string        `json:"difficulty"`
-	GasLimit        uint64        `json:"gasLimit"`
-	GasUsed         uint64        `json:"gasUsed"`
-	Miner           string        `json:"miner"`
-	ExtraData       string        `json:"extraData"`
-	Transactions    []string      `json:"transactions"`
-	TransactionCount int          `json:"transactionCount"`
-	Size            uint64        `json:"size"`
-}
-
-// ParseBlock 从原生区块类型解析为JSONBlock结构
-func ParseBlock(block *types.Block) *JSONBlock {
+	BlockHeader
+	Transactions     []string            `json:"transactions"`
+	TransactionCount int                 `json:"transactionCount"`
+	Uncles           []string            `json:"uncles"`
+	Withdrawals      []*types.Withdrawal `json:"withdrawals,omitempty"`
+	Size             uint64              `json:"size"`
+	// FullTransactions 完整交易列表，只在 fullTx 为true时填写
+	FullTransactions []*Transaction `json:"fullTransactions,omitempty"`
+}
+
+// Block 区块信息结构体，与 JSONBlock 相同
+type Block = JSONBlock
+
+// ParseBlock 从原生区块类型解析为JSONBlock结构，fullTx为true时同时解析完整交易
+func ParseBlock(block *types.Block, fullTx bool) *JSONBlock {
 	transactions := make([]string, len(block.Transactions()))
 	for i, tx := range block.Transactions() {
 		transactions[i] = tx.Hash().Hex()
 	}
 
+	uncles := make([]string, len(block.Uncles()))
+	for i, uncle := range block.Uncles() {
+		uncles[i] = uncle.Hash().Hex()
+	}
+
+	var fullTransactions []*Transaction
+	if fullTx {
+		fullTransactions = make([]*Transaction, len(block.Transactions()))
+		for i, tx := range block.Transactions() {
+			fullTransactions[i] = ParseBlockTransaction(tx, block.Header(), i)
+		}
+	}
+
 	return &JSONBlock{
-		Number:          block.Number().Uint64(),
-		Hash:            block.Hash().Hex(),
-		Timestamp:       time.Unix(int64(block.Time()), 0),
-		ParentHash:      block.ParentHash().Hex(),
-		Difficulty:      block.Difficulty().String(),
-		GasLimit:        block.GasLimit(),
-		GasUsed:         block.GasUsed(),
-		Miner:           block.Coinbase().Hex(),
-		ExtraData:       fmt.Sprintf("%x", block.Extra()),
-		Transactions:    transactions,
+		BlockHeader:      *NewBlockHeader(block.Header()),
+		Transactions:     transactions,
 		TransactionCount: len(transactions),
-		Size:            block.Size(),
+		Uncles:           uncles,
+		Withdrawals:      block.Withdrawals(),
+		Size:             block.Size(),
+		FullTransactions: fullTransactions,
 	}
 }
 
-// BlockHeader 区块头信息结构体
+// GetLatestJSONBlock 获取最新区块并转换为JSONBlock，fullTx为true时包含完整交易
+func (c *Client) GetLatestJSONBlock(ctx context.Context, fullTx bool) (*JSONBlock, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	block, err := c.Client.BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, wrapError(err, "获取最新区块失败")
+	}
+
+	return ParseBlock(block, fullTx), nil
+}
+
+// GetJSONBlockByNumber 根据区块号获取区块并转换为JSONBlock，fullTx为true时包含完整交易
+func (c *Client) GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*JSONBlock, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	block, err := c.Client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, wrapError(err, "获取区块 %d 失败", number)
+	}
+
+	return ParseBlock(block, fullTx), nil
+}
+
+// GetJSONBlockByHash 根据区块哈希获取区块并转换为JSONBlock，fullTx为true时包含完整交易
+func (c *Client) GetJSONBlockByHash(ctx context.Context, hash string, fullTx bool) (*JSONBlock, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	block, err := c.Client.BlockByHash(ctx, common.HexToHash(hash))
+	if err != nil {
+		return nil, wrapError(err, "获取区块 %s 失败", hash)
+	}
+
+	return ParseBlock(block, fullTx), nil
+}
+
+// BlockHeader 区块头信息结构体，包含伦敦、上海和坎昆升级新增的字段（升级前的区块中为空）
 type BlockHeader struct {
-	Number        uint64    `json:"number"`
-	Hash          string    `json:"hash"`
-	Timestamp     time.Time `json:"timestamp"`
-	ParentHash    string    `json:"parentHash"`
-	GasLimit      uint64    `json:"gasLimit"`
-	GasUsed       uint64    `json:"gasUsed"`
-	Miner         string    `json:"miner"`
-}
\ No newline at end of file
+	Number           uint64        `json:"number"`
+	Hash             string        `json:"hash"`
+	ParentHash       string        `json:"parentHash"`
+	Timestamp        time.Time     `json:"timestamp"`
+	Miner            string        `json:"miner"`
+	GasLimit         uint64        `json:"gasLimit"`
+	GasUsed          uint64        `json:"gasUsed"`
+	Difficulty       *big.Int      `json:"difficulty"`
+	ExtraData        hexutil.Bytes `json:"extraData"`
+	StateRoot        string        `json:"stateRoot"`
+	TransactionsRoot string        `json:"transactionsRoot"`
+	ReceiptsRoot     string        `json:"receiptsRoot"`
+	UnclesHash       string        `json:"sha3Uncles"`
+	LogsBloom        hexutil.Bytes `json:"logsBloom"`
+	// MixHash 合并后为信标链提供的随机数 prevRandao
+	MixHash string         `json:"mixHash"`
+	Nonce   hexutil.Uint64 `json:"nonce"`
+	// BaseFeePerGas 伦敦升级（EIP-1559）
+	BaseFeePerGas *big.Int `json:"baseFeePerGas,omitempty"`
+	// WithdrawalsRoot 上海升级（EIP-4895）
+	WithdrawalsRoot string `json:"withdrawalsRoot,omitempty"`
+	// BlobGasUsed、ExcessBlobGas、ParentBeaconBlockRoot 坎昆升级（EIP-4844、EIP-4788）
+	BlobGasUsed           *uint64 `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas         *uint64 `json:"excessBlobGas,omitempty"`
+	ParentBeaconBlockRoot string  `json:"parentBeaconBlockRoot,omitempty"`
+}
+
+// NewBlockHeader 将原始区块头转换为 BlockHeader
+func NewBlockHeader(header *types.Header) *BlockHeader {
+	result := &BlockHeader{
+		Number:           header.Number.Uint64(),
+		Hash:             header.Hash().Hex(),
+		ParentHash:       header.ParentHash.Hex(),
+		Timestamp:        time.Unix(int64(header.Time), 0),
+		Miner:            header.Coinbase.Hex(),
+		GasLimit:         header.GasLimit,
+		GasUsed:          header.GasUsed,
+		Difficulty:       header.Difficulty,
+		ExtraData:        header.Extra,
+		StateRoot:        header.Root.Hex(),
+		TransactionsRoot: header.TxHash.Hex(),
+		ReceiptsRoot:     header.ReceiptHash.Hex(),
+		UnclesHash:       header.UncleHash.Hex(),
+		LogsBloom:        header.Bloom.Bytes(),
+		MixHash:          header.MixDigest.Hex(),
+		Nonce:            hexutil.Uint64(header.Nonce.Uint64()),
+		BaseFeePerGas:    header.BaseFee,
+		BlobGasUsed:      header.BlobGasUsed,
+		ExcessBlobGas:    header.ExcessBlobGas,
+	}
+
+	if header.WithdrawalsHash != nil {
+		result.WithdrawalsRoot = header.WithdrawalsHash.Hex()
+	}
+	if header.ParentBeaconRoot != nil {
+		result.ParentBeaconBlockRoot = header.ParentBeaconRoot.Hex()
+	}
+
+	return result
+}
+
+// ToHeader 还原原生区块头，其哈希与 Hash 字段一致
+func (h *BlockHeader) ToHeader() *types.Header {
+	header := &types.Header{
+		ParentHash:    common.HexToHash(h.ParentHash),
+		UncleHash:     common.HexToHash(h.UnclesHash),
+		Coinbase:      common.HexToAddress(h.Miner),
+		Root:          common.HexToHash(h.StateRoot),
+		TxHash:        common.HexToHash(h.TransactionsRoot),
+		ReceiptHash:   common.HexToHash(h.ReceiptsRoot),
+		Bloom:         types.BytesToBloom(h.LogsBloom),
+		Difficulty:    h.Difficulty,
+		Number:        new(big.Int).SetUint64(h.Number),
+		GasLimit:      h.GasLimit,
+		GasUsed:       h.GasUsed,
+		Time:          uint64(h.Timestamp.Unix()),
+		Extra:         h.ExtraData,
+		MixDigest:     common.HexToHash(h.MixHash),
+		Nonce:         types.EncodeNonce(uint64(h.Nonce)),
+		BaseFee:       h.BaseFeePerGas,
+		BlobGasUsed:   h.BlobGasUsed,
+		ExcessBlobGas: h.ExcessBlobGas,
+	}
+
+	if h.WithdrawalsRoot != "" {
+		root := common.HexToHash(h.WithdrawalsRoot)
+		header.WithdrawalsHash = &root
+	}
+	if h.ParentBeaconBlockRoot != "" {
+		root := common.HexToHash(h.ParentBeaconBlockRoot)
+		header.ParentBeaconRoot = &root
+	}
+
+	return header
+}
@@ -0,0 +1,96 @@
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBlockHeaderRoundTrip(t *testing.T) {
+	var bloom types.Bloom
+	bloom.Add([]byte("Transfer"))
+	zero := uint64(0)
+	blobGasUsed, excessBlobGas := uint64(131072), uint64(393216)
+	withdrawalsRoot := common.HexToHash("0x0a")
+	beaconRoot := common.HexToHash("0x0b")
+
+	base := func(number int64) *types.Header {
+		return &types.Header{
+			ParentHash:  common.HexToHash("0x01"),
+			UncleHash:   types.EmptyUncleHash,
+			Coinbase:    common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5"),
+			Root:        common.HexToHash("0x02"),
+			TxHash:      common.HexToHash("0x03"),
+			ReceiptHash: common.HexToHash("0x04"),
+			Bloom:       bloom,
+			Difficulty:  big.NewInt(0),
+			Number:      big.NewInt(number),
+			GasLimit:    30_000_000,
+			GasUsed:     12_345_678,
+			Time:        1_700_000_000,
+			Extra:       []byte("beaverbuild.org"),
+			MixDigest:   common.HexToHash("0x05"),
+		}
+	}
+
+	preLondon := base(12_000_000)
+	preLondon.Difficulty = big.NewInt(7_000_000_000_000_000)
+	preLondon.Nonce = types.EncodeNonce(0xfedcba9876543210)
+	preLondon.UncleHash = common.HexToHash("0x06")
+	preLondon.Extra = nil
+
+	london := base(15_000_000)
+	london.BaseFee = big.NewInt(25_000_000_000)
+
+	shanghai := base(17_000_000)
+	shanghai.BaseFee = big.NewInt(0)
+	shanghai.WithdrawalsHash = &withdrawalsRoot
+
+	cancun := base(19_500_000)
+	cancun.BaseFee = big.NewInt(7)
+	cancun.WithdrawalsHash = &withdrawalsRoot
+	cancun.BlobGasUsed = &blobGasUsed
+	cancun.ExcessBlobGas = &excessBlobGas
+	cancun.ParentBeaconRoot = &beaconRoot
+
+	// 坎昆升级后不含blob交易的区块，blobGasUsed为0但仍参与哈希
+	cancunNoBlobs := base(19_500_001)
+	cancunNoBlobs.BaseFee = big.NewInt(7)
+	cancunNoBlobs.WithdrawalsHash = &withdrawalsRoot
+	cancunNoBlobs.BlobGasUsed = &zero
+	cancunNoBlobs.ExcessBlobGas = &zero
+	cancunNoBlobs.ParentBeaconRoot = &beaconRoot
+
+	tests := []struct {
+		name   string
+		header *types.Header
+	}{
+		{"伦敦升级前", preLondon},
+		{"伦敦升级", london},
+		{"上海升级", shanghai},
+		{"坎昆升级", cancun},
+		{"坎昆升级无blob", cancunNoBlobs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(NewBlockHeader(tt.header))
+			if err != nil {
+				t.Fatalf("序列化区块头失败: %v", err)
+			}
+			var decoded BlockHeader
+			if err := json.Unmarshal(raw, &decoded); err != nil {
+				t.Fatalf("反序列化区块头失败: %v", err)
+			}
+
+			if decoded.Hash != tt.header.Hash().Hex() {
+				t.Fatalf("Hash = %s, want %s", decoded.Hash, tt.header.Hash().Hex())
+			}
+			if got := decoded.ToHeader().Hash().Hex(); got != decoded.Hash {
+				t.Errorf("ToHeader().Hash() = %s, want %s\n%s", got, decoded.Hash, raw)
+			}
+		})
+	}
+}
@@ -2,202 +2,555 @@ package eth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go-eth-backend/internal/pkg/config"
 )
 
-// Block 区块信息结构
-type Block struct {
-	Number           uint64
-	Hash             string
-	Timestamp        time.Time
-	TransactionCount int
-	GasUsed          uint64
-	Miner            string
-	Size             uint64
-	Difficulty       *big.Int
-	ExtraData        []byte
-}
+const (
+	// DefaultRequestTimeout 单次RPC调用的默认超时
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultReceiptTimeout 等待交易收据的默认超时
+	DefaultReceiptTimeout = 300 * time.Second
+	// DefaultBlockTime 未配置出块间隔时的默认轮询间隔
+	DefaultBlockTime = 12 * time.Second
+)
 
-// BlockHeader 区块头信息
-type BlockHeader struct {
-	ParentHash string
-	GasLimit   uint64
-	Difficulty *big.Int
+// Backend eth.Client 访问节点所用的方法集合，*ethclient.Client 和模拟链的 simulated.Client 都实现了该接口
+type Backend interface {
+	ethereum.BlockNumberReader
+	ethereum.ChainReader
+	ethereum.ChainStateReader
+	ethereum.ContractCaller
+	ethereum.GasEstimator
+	ethereum.GasPricer
+	ethereum.GasPricer1559
+	ethereum.LogFilterer
+	ethereum.PendingStateReader
+	ethereum.TransactionReader
+	ethereum.TransactionSender
+	ethereum.ChainIDReader
 }
 
 // Client 以太坊客户端封装
 type Client struct {
-	Client *ethclient.Client
+	Client Backend
+
+	rpc *rpc.Client // 底层JSON-RPC连接，用于批量请求；直接注入的后端没有时为nil
+
+	network        string
+	networkConfig  config.NetworkConfig
+	requestTimeout time.Duration
+	receiptTimeout time.Duration
+	nonces         *NonceManager
+	pool           *EndpointPool // 配置多个HTTP RPC地址时非nil
+	batchSize      int           // 单个JSON-RPC批量请求包含的调用数
+
+	noBlockReceipts atomic.Bool // 节点不支持 eth_getBlockReceipts
+
+	errorsMu     sync.RWMutex
+	customErrors map[[4]byte]abi.Error // 已注册的合约自定义错误，按选择器索引
+}
+
+// Reader 只读查询接口，*Client 和缓存装饰器都实现了该接口
+type Reader interface {
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	GetFinalizedBlockNumber(ctx context.Context) (uint64, error)
+	GetLatestJSONBlock(ctx context.Context, fullTx bool) (*JSONBlock, error)
+	GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*JSONBlock, error)
+	GetJSONBlockByHash(ctx context.Context, hash string, fullTx bool) (*JSONBlock, error)
+	GetTransactionByHash(ctx context.Context, hash string) (*types.Transaction, bool, error)
+	GetTransactionReceipt(ctx context.Context, hash string) (*types.Receipt, error)
+	GetBalance(ctx context.Context, address string) (*big.Int, error)
+	GetNonce(ctx context.Context, address string) (uint64, error)
+	GetGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// NewClient 创建新的以太坊客户端，使用默认超时
+// 传入多个HTTP RPC地址时通过 EndpointPool 在节点间负载均衡和故障转移
+func NewClient(rpcURLs ...string) (*Client, error) {
+	switch len(rpcURLs) {
+	case 0:
+		return nil, errors.New("未配置RPC地址")
+	case 1:
+		return newClientWithPolicy(rpcURLs[0], DefaultRetryPolicy())
+	default:
+		return newPooledClient(rpcURLs, DefaultHealthCheckInterval, DefaultRetryPolicy())
+	}
+}
+
+// newClientWithPolicy 创建连接单个节点的客户端，HTTP请求经过限流重试中间件
+// ws/ipc连接不经过该中间件
+func newClientWithPolicy(rpcURL string, policy RetryPolicy) (*Client, error) {
+	httpClient := &http.Client{Transport: NewRateLimitTransport(http.DefaultTransport, policy)}
+	rpcClient, err := rpc.DialOptions(context.Background(), rpcURL, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, wrapError(err, "连接以太坊节点失败")
+	}
+	return newClient(ethclient.NewClient(rpcClient)), nil
+}
+
+// newPooledClient 创建经由节点池访问多个HTTP RPC节点的客户端
+// 每个节点的请求分别限流但不重试，429、5xx和网络错误先由节点池换节点；所有节点都失败后再按策略整体退避重试
+func newPooledClient(rpcURLs []string, healthCheckInterval time.Duration, policy RetryPolicy) (*Client, error) {
+	pool, err := NewEndpointPool(rpcURLs, healthCheckInterval, NewRateLimitTransport(http.DefaultTransport, policy.limitOnly()))
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求实际发往哪个节点由pool决定，这里的地址只用于构造HTTP请求
+	transport := NewRateLimitTransport(pool, policy.retryOnly())
+	rpcClient, err := rpc.DialOptions(context.Background(), rpcURLs[0], rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		pool.Close()
+		return nil, wrapError(err, "连接以太坊节点失败")
+	}
+
+	client := newClient(ethclient.NewClient(rpcClient))
+	client.pool = pool
+	return client, nil
+}
+
+// NewClientFromBackend 使用默认配置封装已有的节点后端，如模拟链的 simulated.Client
+// 后端不是 *ethclient.Client 时批量请求退化为逐个调用
+func NewClientFromBackend(backend Backend) *Client {
+	return newClient(backend)
+}
+
+// newClient 使用默认配置封装节点后端
+func newClient(client Backend) *Client {
+	var rpcClient *rpc.Client
+	if raw, ok := client.(*ethclient.Client); ok {
+		rpcClient = raw.Client()
+	}
+
+	return &Client{
+		Client:         client,
+		rpc:            rpcClient,
+		requestTimeout: DefaultRequestTimeout,
+		receiptTimeout: DefaultReceiptTimeout,
+		batchSize:      DefaultBatchSize,
+		nonces:         NewNonceManager(client, DefaultNonceIdleTimeout),
+		customErrors:   make(map[[4]byte]abi.Error),
+	}
+}
+
+// NewClientWithConfig 创建新的以太坊客户端，超时取自配置
+func NewClientWithConfig(rpcURL string, timeouts config.TimeoutsConfig) (*Client, error) {
+	return newClientWithConfig(rpcURL, timeouts, DefaultRetryPolicy())
+}
+
+// newClientWithConfig 按超时配置和重试策略创建连接单个节点的客户端
+func newClientWithConfig(rpcURL string, timeouts config.TimeoutsConfig, policy RetryPolicy) (*Client, error) {
+	client, err := newClientWithPolicy(rpcURL, policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.applyTimeouts(timeouts); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// applyTimeouts 应用配置中的超时，未配置的项保持默认值
+func (c *Client) applyTimeouts(timeouts config.TimeoutsConfig) error {
+	requestTimeout, err := timeouts.GetRequestTimeout()
+	if err != nil {
+		return err
+	}
+
+	receiptTimeout, err := timeouts.GetReceiptTimeout()
+	if err != nil {
+		return err
+	}
+
+	nonceIdleTimeout, err := timeouts.GetNonceIdleTimeout()
+	if err != nil {
+		return err
+	}
+
+	if requestTimeout > 0 {
+		c.requestTimeout = requestTimeout
+	}
+	if receiptTimeout > 0 {
+		c.receiptTimeout = receiptTimeout
+	}
+	c.nonces = NewNonceManager(c.Client, nonceIdleTimeout)
+
+	return nil
+}
+
+// NewClientForNetwork 根据网络名称创建客户端
+// 配置了多个HTTP RPC地址时通过 EndpointPool 按节点健康状况路由每次调用，
+// 否则依次尝试配置的RPC地址；连接成功后校验节点链ID与配置一致
+func NewClientForNetwork(cfg *config.Config, name string) (*Client, error) {
+	network, err := cfg.GetNetwork(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(network.RPCURLs) == 0 {
+		return nil, fmt.Errorf("网络 %s 未配置RPC地址", name)
+	}
+
+	policy, err := NewRetryPolicy(network.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	if len(network.RPCURLs) > 1 && allHTTP(network.RPCURLs) {
+		client, err := newPooledClientForNetwork(cfg, name, network, policy)
+		if err == nil || errors.Is(err, ErrChainIDMismatch) {
+			return client, err
+		}
+		lastErr = err
+	} else {
+		for _, rpcURL := range network.RPCURLs {
+			client, err := newClientWithConfig(rpcURL, cfg.GetTimeoutsConfig(), policy)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			if err := client.bindNetwork(name, network); err != nil {
+				client.Close()
+				if errors.Is(err, ErrChainIDMismatch) {
+					return nil, err
+				}
+				lastErr = err
+				continue
+			}
+			return client, nil
+		}
+	}
+
+	if !errors.Is(lastErr, ErrRPCUnavailable) {
+		lastErr = fmt.Errorf("%w: %w", ErrRPCUnavailable, lastErr)
+	}
+	return nil, fmt.Errorf("网络 %s 的所有RPC地址均不可用: %w", name, lastErr)
+}
+
+// newPooledClientForNetwork 创建经由节点池访问网络全部RPC地址的客户端，按出块间隔做健康检查
+func newPooledClientForNetwork(cfg *config.Config, name string, network config.NetworkConfig, policy RetryPolicy) (*Client, error) {
+	interval, err := network.GetBlockTime()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newPooledClient(network.RPCURLs, interval, policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.applyTimeouts(cfg.GetTimeoutsConfig()); err != nil {
+		client.Close()
+		return nil, err
+	}
+	if err := client.bindNetwork(name, network); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// bindNetwork 校验节点链ID与网络配置一致，并记录网络信息
+func (c *Client) bindNetwork(name string, network config.NetworkConfig) error {
+	chainID, err := c.ChainID(context.Background())
+	if err != nil {
+		return err
+	}
+	if chainID.Cmp(big.NewInt(network.ChainID)) != 0 {
+		return fmt.Errorf("网络 %s %w: 配置为 %d, 节点返回 %s", name, ErrChainIDMismatch, network.ChainID, chainID)
+	}
+
+	c.network = name
+	c.networkConfig = network
+	if network.BatchSize > 0 {
+		c.batchSize = network.BatchSize
+	}
+	return nil
+}
+
+// allHTTP 是否全部为http/https地址，节点池不支持ws/ipc
+func allHTTP(rpcURLs []string) bool {
+	for _, rpcURL := range rpcURLs {
+		if !strings.HasPrefix(rpcURL, "http://") && !strings.HasPrefix(rpcURL, "https://") {
+			return false
+		}
+	}
+	return true
 }
 
-// NewClient 创建新的以太坊客户端
-func NewClient(rpcURL string) (*Client, error) {
-	client, err := ethclient.Dial(rpcURL)
+// Network 返回客户端所连接的网络名称
+func (c *Client) Network() string {
+	return c.network
+}
+
+// NetworkConfig 返回客户端所连接网络的配置
+func (c *Client) NetworkConfig() config.NetworkConfig {
+	return c.networkConfig
+}
+
+// Nonces 返回客户端使用的nonce管理器
+func (c *Client) Nonces() *NonceManager {
+	return c.nonces
+}
+
+// ChainID 获取节点的链ID
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	chainID, err := c.Client.ChainID(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("连接以太坊节点失败: %v", err)
+		return nil, wrapError(err, "获取链ID失败")
 	}
 
-	return &Client{Client: client}, nil
+	return chainID, nil
+}
+
+// withTimeout 为单次调用附加配置的超时，调用方的截止时间更早时以调用方为准
+func (c *Client) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// blockTime 返回网络配置的出块间隔，未配置时使用默认值，用作轮询间隔
+func (c *Client) blockTime() (time.Duration, error) {
+	interval, err := c.networkConfig.GetBlockTime()
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		interval = DefaultBlockTime
+	}
+	return interval, nil
 }
 
 // GetRawClient 获取原始以太坊客户端
-func (c *Client) GetRawClient() *ethclient.Client {
+func (c *Client) GetRawClient() Backend {
 	return c.Client
 }
 
+// supportsSubscriptions 连接是否支持 eth_subscribe，直接注入的后端（进程内连接）总是支持
+func (c *Client) supportsSubscriptions() bool {
+	if c.rpc == nil {
+		return true
+	}
+	return c.rpc.SupportsSubscriptions()
+}
+
 // Close 关闭客户端连接
 func (c *Client) Close() {
-	if c.Client != nil {
-		c.Client.Close()
+	if closer, ok := c.Client.(interface{ Close() }); ok {
+		closer.Close()
+	}
+	if c.pool != nil {
+		c.pool.Close()
+	}
+}
+
+// Endpoints 返回各RPC节点的健康状态，只连接单个节点时返回nil
+func (c *Client) Endpoints() []EndpointStatus {
+	if c.pool == nil {
+		return nil
 	}
+	return c.pool.Status()
+}
+
+// GetLatestBlockNumber 获取最新区块号
+func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	number, err := c.Client.BlockNumber(ctx)
+	if err != nil {
+		return 0, wrapError(err, "获取最新区块号失败")
+	}
+
+	return number, nil
+}
+
+// GetFinalizedBlockNumber 获取节点标记为 finalized 的区块号，该区块及之前的区块不会再被重组
+// 合并前的链和部分L2节点不支持 finalized 标签，此时返回错误
+func (c *Client) GetFinalizedBlockNumber(ctx context.Context) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	header, err := c.Client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		return 0, wrapError(err, "获取最终确认区块号失败")
+	}
+
+	return header.Number.Uint64(), nil
 }
 
 // GetLatestBlock 获取最新区块
-func (c *Client) GetLatestBlock() (*Block, error) {
-	header, err := c.Client.HeaderByNumber(context.Background(), nil)
+func (c *Client) GetLatestBlock(ctx context.Context) (*Block, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	header, err := c.Client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("获取最新区块头失败: %v", err)
+		return nil, wrapError(err, "获取最新区块头失败")
 	}
 
-	block, err := c.Client.BlockByHash(context.Background(), header.Hash())
+	block, err := c.Client.BlockByHash(ctx, header.Hash())
 	if err != nil {
-		return nil, fmt.Errorf("获取区块详情失败: %v", err)
+		return nil, wrapError(err, "获取区块详情失败")
 	}
 
-	return &Block{
-		Number:           block.Number().Uint64(),
-		Hash:             block.Hash().Hex(),
-		Timestamp:        time.Unix(int64(block.Time()), 0),
-		TransactionCount: len(block.Transactions()),
-		GasUsed:          block.GasUsed(),
-		Miner:            block.Coinbase().Hex(),
-		Size:             block.Size(),
-		Difficulty:       block.Difficulty(),
-		ExtraData:        block.Extra(),
-	}, nil
+	return ParseBlock(block, false), nil
 }
 
 // GetBlockByNumber 根据区块号获取区块
-func (c *Client) GetBlockByNumber(number uint64) (*Block, error) {
-	block, err := c.Client.BlockByNumber(context.Background(), big.NewInt(int64(number)))
+func (c *Client) GetBlockByNumber(ctx context.Context, number uint64) (*Block, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	block, err := c.Client.BlockByNumber(ctx, big.NewInt(int64(number)))
 	if err != nil {
-		return nil, fmt.Errorf("获取区块 %d 失败: %v", number, err)
+		return nil, wrapError(err, "获取区块 %d 失败", number)
 	}
 
-	return &Block{
-		Number:           block.Number().Uint64(),
-		Hash:             block.Hash().Hex(),
-		Timestamp:        time.Unix(int64(block.Time()), 0),
-		TransactionCount: len(block.Transactions()),
-		GasUsed:          block.GasUsed(),
-		Miner:            block.Coinbase().Hex(),
-		Size:             block.Size(),
-		Difficulty:       block.Difficulty(),
-		ExtraData:        block.Extra(),
-	}, nil
+	return ParseBlock(block, false), nil
 }
 
-// GetBlockHeaderByNumber 根据区块号获取区块头
-func (c *Client) GetBlockHeaderByNumber(number uint64) (*BlockHeader, error) {
-	header, err := c.Client.HeaderByNumber(context.Background(), big.NewInt(int64(number)))
+// GetRawBlockByNumber 根据区块号获取包含完整交易的原生区块
+func (c *Client) GetRawBlockByNumber(ctx context.Context, number uint64) (*types.Block, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	block, err := c.Client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, wrapError(err, "获取区块 %d 失败", number)
+	}
+
+	return block, nil
+}
+
+// GetBlockHeaderByNumber 根据区块号获取完整区块头
+func (c *Client) GetBlockHeaderByNumber(ctx context.Context, number uint64) (*BlockHeader, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	header, err := c.Client.HeaderByNumber(ctx, big.NewInt(int64(number)))
 	if err != nil {
-		return nil, fmt.Errorf("获取区块头 %d 失败: %v", number, err)
+		return nil, wrapError(err, "获取区块头 %d 失败", number)
 	}
 
-	return &BlockHeader{
-		ParentHash: header.ParentHash.Hex(),
-		GasLimit:   header.GasLimit,
-		Difficulty: header.Difficulty,
-	}, nil
+	return NewBlockHeader(header), nil
 }
 
 // GetBlockTransactionCount 获取区块中的交易数量
-func (c *Client) GetBlockTransactionCount(number uint64) (int, error) {
-	block, err := c.Client.BlockByNumber(context.Background(), big.NewInt(int64(number)))
+func (c *Client) GetBlockTransactionCount(ctx context.Context, number uint64) (int, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	block, err := c.Client.BlockByNumber(ctx, big.NewInt(int64(number)))
 	if err != nil {
-		return 0, fmt.Errorf("获取区块 %d 失败: %v", number, err)
+		return 0, wrapError(err, "获取区块 %d 失败", number)
 	}
 
 	return len(block.Transactions()), nil
 }
 
 // GetBlockByHash 根据区块哈希获取区块
-func (c *Client) GetBlockByHash(hash string) (*Block, error) {
+func (c *Client) GetBlockByHash(ctx context.Context, hash string) (*Block, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	blockHash := common.HexToHash(hash)
-	block, err := c.Client.BlockByHash(context.Background(), blockHash)
+	block, err := c.Client.BlockByHash(ctx, blockHash)
 	if err != nil {
-		return nil, fmt.Errorf("获取区块 %s 失败: %v", hash, err)
+		return nil, wrapError(err, "获取区块 %s 失败", hash)
 	}
 
-	return &Block{
-		Number:           block.Number().Uint64(),
-		Hash:             block.Hash().Hex(),
-		Timestamp:        time.Unix(int64(block.Time()), 0),
-		TransactionCount: len(block.Transactions()),
-		GasUsed:          block.GasUsed(),
-		Miner:            block.Coinbase().Hex(),
-		Size:             block.Size(),
-		Difficulty:       block.Difficulty(),
-		ExtraData:        block.Extra(),
-	}, nil
+	return ParseBlock(block, false), nil
 }
 
 // GetTransactionByHash 根据交易哈希获取交易信息
-func (c *Client) GetTransactionByHash(hash string) (*types.Transaction, bool, error) {
+func (c *Client) GetTransactionByHash(ctx context.Context, hash string) (*types.Transaction, bool, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	txHash := common.HexToHash(hash)
-	tx, isPending, err := c.Client.TransactionByHash(context.Background(), txHash)
+	tx, isPending, err := c.Client.TransactionByHash(ctx, txHash)
 	if err != nil {
-		return nil, false, fmt.Errorf("获取交易 %s 失败: %v", hash, err)
+		return nil, false, wrapError(err, "获取交易 %s 失败", hash)
 	}
 
 	return tx, isPending, nil
 }
 
 // GetTransactionReceipt 获取交易收据
-func (c *Client) GetTransactionReceipt(hash string) (*types.Receipt, error) {
+func (c *Client) GetTransactionReceipt(ctx context.Context, hash string) (*types.Receipt, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	txHash := common.HexToHash(hash)
-	receipt, err := c.Client.TransactionReceipt(context.Background(), txHash)
+	receipt, err := c.Client.TransactionReceipt(ctx, txHash)
 	if err != nil {
-		return nil, fmt.Errorf("获取交易收据 %s 失败: %v", hash, err)
+		return nil, wrapError(err, "获取交易收据 %s 失败", hash)
 	}
 
 	return receipt, nil
 }
 
 // GetBalance 获取账户余额
-func (c *Client) GetBalance(address string) (*big.Int, error) {
+func (c *Client) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	account := common.HexToAddress(address)
-	balance, err := c.Client.BalanceAt(context.Background(), account, nil)
+	balance, err := c.Client.BalanceAt(ctx, account, nil)
 	if err != nil {
-		return nil, fmt.Errorf("获取账户余额失败: %v", err)
+		return nil, wrapError(err, "获取账户余额失败")
 	}
 
 	return balance, nil
 }
 
 // GetNonce 获取账户nonce
-func (c *Client) GetNonce(address string) (uint64, error) {
+func (c *Client) GetNonce(ctx context.Context, address string) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	account := common.HexToAddress(address)
-	nonce, err := c.Client.NonceAt(context.Background(), account, nil)
+	nonce, err := c.Client.NonceAt(ctx, account, nil)
 	if err != nil {
-		return 0, fmt.Errorf("获取账户nonce失败: %v", err)
+		return 0, wrapError(err, "获取账户nonce失败")
 	}
 
 	return nonce, nil
 }
 
 // GetGasPrice 获取当前gas价格
-func (c *Client) GetGasPrice() (*big.Int, error) {
-	gasPrice, err := c.Client.SuggestGasPrice(context.Background())
+func (c *Client) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	gasPrice, err := c.Client.SuggestGasPrice(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("获取gas价格失败: %v", err)
+		return nil, wrapError(err, "获取gas价格失败")
 	}
 
 	return gasPrice, nil
-}
\ No newline at end of file
+}
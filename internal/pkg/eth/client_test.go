@@ -0,0 +1,85 @@
+package eth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-eth-backend/internal/pkg/config"
+)
+
+// networkConfigFor 返回名为local、RPC地址指向假节点的配置
+func networkConfigFor(chainID int64, nodes ...*fakeNode) *config.Config {
+	noRetry := 0
+	network := config.NetworkConfig{
+		ChainID:   chainID,
+		BatchSize: 7,
+		RateLimit: config.RateLimitConfig{MaxRetries: &noRetry},
+	}
+	for _, node := range nodes {
+		network.RPCURLs = append(network.RPCURLs, node.URL)
+	}
+	return &config.Config{Ethereum: config.EthereumConfig{Networks: config.NetworksConfig{"local": network}}}
+}
+
+func TestNewClientForNetwork(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	down := newFakeNode(t, mempool, 10)
+	down.set(func(n *fakeNode) { n.status = http.StatusServiceUnavailable })
+
+	// 假节点的链ID为1337
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		network string
+		wantErr error // nil表示应成功
+		pooled  bool
+	}{
+		{"单节点链ID一致", networkConfigFor(1337, newFakeNode(t, mempool, 10)), "local", nil, false},
+		{"单节点链ID不一致", networkConfigFor(1, newFakeNode(t, mempool, 10)), "local", ErrChainIDMismatch, false},
+		{"节点池链ID一致", networkConfigFor(1337, newFakeNode(t, mempool, 10), newFakeNode(t, mempool, 10)), "local", nil, true},
+		{"节点池链ID不一致", networkConfigFor(1, newFakeNode(t, mempool, 10), newFakeNode(t, mempool, 10)), "local", ErrChainIDMismatch, true},
+		{"节点不可用", networkConfigFor(1337, down), "local", ErrRPCUnavailable, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClientForNetwork(tt.cfg, tt.network)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if client != nil {
+					t.Error("失败时不应返回客户端")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("创建客户端失败: %v", err)
+			}
+			defer client.Close()
+
+			if client.Network() != "local" || client.batchSize != 7 {
+				t.Errorf("网络 = %s, batchSize = %d, want local, 7", client.Network(), client.batchSize)
+			}
+			if (client.pool != nil) != tt.pooled {
+				t.Errorf("使用节点池 = %v, want %v", client.pool != nil, tt.pooled)
+			}
+		})
+	}
+}
+
+func TestNewClientForNetworkInvalidConfig(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	cfg := networkConfigFor(1337, newFakeNode(t, mempool, 10))
+
+	_, err := NewClientForNetwork(cfg, "mainnet")
+	if err == nil || !strings.Contains(err.Error(), "mainnet") || errors.Is(err, ErrRPCUnavailable) {
+		t.Errorf("未知网络 err = %v, want 未找到网络配置", err)
+	}
+
+	cfg.Ethereum.Networks["empty"] = config.NetworkConfig{ChainID: 1337}
+	if _, err := NewClientForNetwork(cfg, "empty"); err == nil || !strings.Contains(err.Error(), "未配置RPC地址") {
+		t.Errorf("没有RPC地址 err = %v, want 未配置RPC地址", err)
+	}
+}
@@ -0,0 +1,107 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrReorged 交易所在区块因链重组已不在规范链上
+var ErrReorged = errors.New("交易已被链重组移出规范链")
+
+// ReorgError 交易被链重组移出规范链的详细信息
+type ReorgError struct {
+	TxHash      common.Hash
+	BlockNumber uint64
+	BlockHash   common.Hash // 交易原先所在的区块
+}
+
+// Error 实现error接口
+func (e *ReorgError) Error() string {
+	return fmt.Sprintf("交易 %s 所在区块 #%d (%s) 已被链重组移出规范链",
+		e.TxHash.Hex(), e.BlockNumber, e.BlockHash.Hex())
+}
+
+// Unwrap 支持 errors.Is(err, ErrReorged)
+func (e *ReorgError) Unwrap() error {
+	return ErrReorged
+}
+
+// WaitForConfirmations 等待交易所在区块之上再产生n个区块
+// n为0时使用网络配置的confirmations；每轮都会校验收据中的区块哈希仍在规范链上，
+// 交易被重组移出时返回 *ReorgError（可用 errors.Is(err, ErrReorged) 判断）
+func (c *Client) WaitForConfirmations(ctx context.Context, txHash string, n uint64) (*types.Receipt, error) {
+	if n == 0 {
+		n = c.networkConfig.Confirmations
+	}
+
+	interval, err := c.blockTime()
+	if err != nil {
+		return nil, err
+	}
+
+	// 在收据超时的基础上为每个确认区块预留一个出块间隔
+	ctx, cancel := c.withTimeout(ctx, c.receiptTimeout+time.Duration(n)*interval)
+	defer cancel()
+
+	hash := common.HexToHash(txHash)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var seen *types.Receipt
+	for {
+		receipt, err := c.Client.TransactionReceipt(ctx, hash)
+		switch {
+		case errors.Is(err, ethereum.NotFound):
+			// 已经打包过的交易又查不到收据，说明所在区块被重组掉了
+			if seen != nil {
+				return nil, &ReorgError{TxHash: hash, BlockNumber: seen.BlockNumber.Uint64(), BlockHash: seen.BlockHash}
+			}
+		case err != nil && isIndexing(err):
+			// 节点仍在建立交易索引，下一轮重试
+		case err != nil:
+			return nil, wrapError(err, "获取交易收据 %s 失败", txHash)
+		default:
+			seen = receipt
+			confirmed, err := c.checkConfirmations(ctx, receipt, n)
+			if err != nil {
+				return nil, err
+			}
+			if confirmed {
+				return receipt, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("等待交易 %s 确认超时: %w", txHash, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkConfirmations 校验收据所在区块仍在规范链上，并判断确认数是否已满足
+func (c *Client) checkConfirmations(ctx context.Context, receipt *types.Receipt, n uint64) (bool, error) {
+	number := receipt.BlockNumber.Uint64()
+
+	header, err := c.Client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil && !errors.Is(err, ethereum.NotFound) {
+		return false, wrapError(err, "获取区块头 #%d 失败", number)
+	}
+	if header == nil || header.Hash() != receipt.BlockHash {
+		return false, &ReorgError{TxHash: receipt.TxHash, BlockNumber: number, BlockHash: receipt.BlockHash}
+	}
+
+	head, err := c.Client.BlockNumber(ctx)
+	if err != nil {
+		return false, wrapError(err, "获取最新区块号失败")
+	}
+
+	return head >= number+n, nil
+}
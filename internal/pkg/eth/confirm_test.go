@@ -0,0 +1,99 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"go-eth-backend/internal/pkg/config"
+)
+
+func TestWaitForConfirmations(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{BlockTime: "10ms"})
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	hash, err := client.SendTransaction(ctx, signer, to.Hex(), big.NewInt(1))
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	mine(t, backend, 10*time.Millisecond)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	receipt, err := client.WaitForConfirmations(waitCtx, hash, 3)
+	if err != nil {
+		t.Fatalf("等待确认失败: %v", err)
+	}
+
+	head, err := client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		t.Fatalf("获取最新区块号失败: %v", err)
+	}
+	if head < receipt.BlockNumber.Uint64()+3 {
+		t.Errorf("最新区块 %d 不足交易区块 %d 之上3个确认", head, receipt.BlockNumber.Uint64())
+	}
+}
+
+func TestWaitForConfirmationsReorg(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{BlockTime: "20ms"})
+
+	genesis, err := client.Client.HeaderByNumber(ctx, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("获取创世区块失败: %v", err)
+	}
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	sent, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1), GasLimit: params.TxGas})
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	backend.Commit()
+
+	errs := make(chan error, 1)
+	go func() {
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		_, err := client.WaitForConfirmations(waitCtx, sent.Hash, 10)
+		errs <- err
+	}()
+
+	// 等待第一轮轮询看到收据
+	time.Sleep(50 * time.Millisecond)
+
+	// 从创世区块分叉，以相同nonce和更高费用打包另一笔交易，原交易不再能被打包
+	if err := backend.Fork(genesis.Hash()); err != nil {
+		t.Fatalf("分叉失败: %v", err)
+	}
+	conflicting := TxRequest{
+		To:        &to,
+		Value:     big.NewInt(2),
+		Nonce:     sent.Nonce,
+		GasLimit:  params.TxGas,
+		GasTipCap: new(big.Int).Mul(sent.MaxPriorityFeePerGas, big.NewInt(2)),
+		GasFeeCap: new(big.Int).Mul(sent.MaxFeePerGas, big.NewInt(2)),
+	}
+	if _, err := client.Send(ctx, signer, conflicting); err != nil {
+		t.Fatalf("发送冲突交易失败: %v", err)
+	}
+	backend.Commit()
+	backend.Commit()
+
+	select {
+	case err := <-errs:
+		var reorgErr *ReorgError
+		if !errors.As(err, &reorgErr) || !errors.Is(err, ErrReorged) {
+			t.Fatalf("err = %v, want *ReorgError", err)
+		}
+		if reorgErr.TxHash.Hex() != sent.Hash || reorgErr.BlockNumber != 1 {
+			t.Errorf("ReorgError = %+v, want 交易 %s 位于区块 #1", reorgErr, sent.Hash)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("等待链重组错误超时")
+	}
+}
@@ -0,0 +1,184 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Contract 按ABI与合约交互，无需abigen生成的绑定代码
+// 方法参数可以是Go类型，也可以是JSON值或字符串，按ABI类型转换（见 ConvertArgs）
+type Contract struct {
+	client  *Client
+	address common.Address
+	abi     abi.ABI
+	logs    *Logs
+}
+
+// NewContract 创建合约客户端，并注册ABI中的自定义错误用于解码回滚原因
+func NewContract(client *Client, address common.Address, contractABI abi.ABI) *Contract {
+	client.RegisterErrorABI(contractABI)
+	return &Contract{
+		client:  client,
+		address: address,
+		abi:     contractABI,
+		logs:    NewLogs(client, address, contractABI),
+	}
+}
+
+// NewContractFromJSON 使用ABI JSON创建合约客户端
+func NewContractFromJSON(client *Client, address common.Address, abiJSON []byte) (*Contract, error) {
+	contractABI, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("解析合约ABI失败: %w", err)
+	}
+	return NewContract(client, address, contractABI), nil
+}
+
+// LoadContract 使用ABI文件（如 contracts/build/Counter.abi）创建合约客户端
+func LoadContract(client *Client, address common.Address, abiPath string) (*Contract, error) {
+	contractABI, err := LoadABI(abiPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewContract(client, address, contractABI), nil
+}
+
+// LoadABI 读取并解析合约ABI文件
+func LoadABI(path string) (abi.ABI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("读取ABI文件失败: %w", err)
+	}
+
+	contractABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("解析ABI文件 %s 失败: %w", path, err)
+	}
+	return contractABI, nil
+}
+
+// DeployContract 以普通合约创建交易部署合约，args为构造函数参数，转换规则同 Contract.Call
+// 合约地址由发送账户和nonce决定；需要跨链相同的地址时使用 Deployer
+// 返回的合约客户端在部署交易确认前即可使用地址，调用方需自行等待交易确认
+func DeployContract(ctx context.Context, client *Client, signer Signer, contractABI abi.ABI, bytecode []byte, req TxRequest, args ...interface{}) (*Contract, *Transaction, error) {
+	initCode, err := InitCode(contractABI, bytecode, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.To = nil
+	req.Data = initCode
+	tx, err := client.Send(ctx, signer, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewContract(client, common.HexToAddress(tx.ContractAddress), contractABI), tx, nil
+}
+
+// Address 返回合约地址
+func (c *Contract) Address() common.Address {
+	return c.address
+}
+
+// ABI 返回合约ABI
+func (c *Contract) ABI() abi.ABI {
+	return c.abi
+}
+
+// Call 以eth_call在最新区块上调用合约方法（不发送交易），返回按ABI解码的输出
+func (c *Contract) Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := c.pack(method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	callCtx, cancel := c.client.withTimeout(ctx, c.client.requestTimeout)
+	defer cancel()
+
+	output, err := c.client.Client.CallContract(callCtx, ethereum.CallMsg{To: &c.address, Data: input}, nil)
+	if err != nil {
+		return nil, c.client.wrapCallError(err, "调用合约方法 %s 失败", method)
+	}
+
+	results, err := c.abi.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("解码方法 %s 的返回值失败: %w", method, err)
+	}
+	return results, nil
+}
+
+// Transact 发送调用合约方法的交易，gas、nonce和费用由 Client.Send 自动补齐
+func (c *Contract) Transact(ctx context.Context, signer Signer, method string, args ...interface{}) (*Transaction, error) {
+	return c.TransactWith(ctx, signer, TxRequest{}, method, args...)
+}
+
+// TransactWith 与Transact相同，使用req中指定的value、gas、nonce和费用，req的To和Data会被覆盖
+func (c *Contract) TransactWith(ctx context.Context, signer Signer, req TxRequest, method string, args ...interface{}) (*Transaction, error) {
+	input, err := c.pack(method, args)
+	if err != nil {
+		return nil, err
+	}
+
+	req.To = &c.address
+	req.Data = input
+	return c.client.Send(ctx, signer, req)
+}
+
+// pack 按ABI转换参数并编码方法调用
+func (c *Contract) pack(method string, args []interface{}) ([]byte, error) {
+	m, ok := c.abi.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("合约ABI中不存在方法 %s", method)
+	}
+
+	converted, err := ConvertArgs(m.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("方法 %s 的%w", method, err)
+	}
+
+	input, err := c.abi.Pack(method, converted...)
+	if err != nil {
+		return nil, fmt.Errorf("编码方法 %s 的参数失败: %w", method, err)
+	}
+	return input, nil
+}
+
+// Events 查询 [from, to] 区块范围内本合约的事件，参数含义同 Logs.Query
+func (c *Contract) Events(ctx context.Context, from uint64, to *big.Int, events ...string) ([]*Event, error) {
+	return c.logs.Query(ctx, from, to, events...)
+}
+
+// SubscribeEvents 订阅本合约新产生的事件，参数含义同 Logs.Subscribe
+func (c *Contract) SubscribeEvents(ctx context.Context, events ...string) (<-chan *Event, error) {
+	return c.logs.Subscribe(ctx, events...)
+}
+
+// DecodeEvent 按合约ABI解码日志
+func (c *Contract) DecodeEvent(log types.Log) (*Event, error) {
+	return DecodeLog(c.abi, log)
+}
+
+// DecodeReceipt 解码交易收据中由本合约产生的事件，其他合约的日志被忽略
+func (c *Contract) DecodeReceipt(receipt *types.Receipt) ([]*Event, error) {
+	events := make([]*Event, 0, len(receipt.Logs))
+	for _, log := range receipt.Logs {
+		if log.Address != c.address {
+			continue
+		}
+		event, err := DecodeLog(c.abi, *log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,267 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 计数器合约事件名称
+const (
+	CounterEventIncremented = "CountIncremented"
+	CounterEventDecremented = "CountDecremented"
+	CounterEventReset       = "CountReset"
+)
+
+// counterMethods CounterService依赖的合约方法，创建服务时校验ABI
+var counterMethods = []string{"increment", "decrement", "reset", "getCount", "getOwner", "transferOwnership", "destroy"}
+
+// counterEvents CounterService解码的合约事件
+var counterEvents = []string{CounterEventIncremented, CounterEventDecremented, CounterEventReset}
+
+// errNoSigner 只读的CounterService无法发送交易
+var errNoSigner = errors.New("未配置签名账户，无法发送交易")
+
+// CounterEvent 计数器合约事件，三种事件的参数相同
+type CounterEvent struct {
+	Name        string         `json:"name"`
+	NewCount    *big.Int       `json:"newCount"`
+	By          common.Address `json:"by"`
+	BlockNumber uint64         `json:"blockNumber"`
+	TxHash      string         `json:"transactionHash"`
+	LogIndex    uint           `json:"logIndex"`
+}
+
+// CounterResult 计数器交易的执行结果
+type CounterResult struct {
+	TxHash      string          `json:"transactionHash"`
+	BlockNumber uint64          `json:"blockNumber"`
+	Status      uint64          `json:"status"`
+	GasUsed     uint64          `json:"gasUsed"`
+	Events      []*CounterEvent `json:"events"`
+}
+
+// CounterService 计数器合约（contracts/build/Counter.abi）的类型化客户端
+// 发送交易使用创建时指定的签名账户；仅限所有者的方法在发送前检查签名账户是否为合约所有者
+type CounterService struct {
+	contract *Contract
+	signer   Signer
+}
+
+// NewCounterService 创建计数器合约服务，signer为nil时只能查询
+func NewCounterService(client *Client, address common.Address, contractABI abi.ABI, signer Signer) (*CounterService, error) {
+	for _, method := range counterMethods {
+		if _, ok := contractABI.Methods[method]; !ok {
+			return nil, fmt.Errorf("合约ABI中不存在方法 %s", method)
+		}
+	}
+	for _, event := range counterEvents {
+		if _, ok := contractABI.Events[event]; !ok {
+			return nil, fmt.Errorf("合约ABI中不存在事件 %s", event)
+		}
+	}
+
+	return &CounterService{
+		contract: NewContract(client, address, contractABI),
+		signer:   signer,
+	}, nil
+}
+
+// LoadCounterService 使用ABI文件创建计数器合约服务
+func LoadCounterService(client *Client, address common.Address, abiPath string, signer Signer) (*CounterService, error) {
+	contractABI, err := LoadABI(abiPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewCounterService(client, address, contractABI, signer)
+}
+
+// Address 返回合约地址
+func (s *CounterService) Address() common.Address {
+	return s.contract.Address()
+}
+
+// Signer 返回发送交易使用的签名账户，未配置时为nil
+func (s *CounterService) Signer() Signer {
+	return s.signer
+}
+
+// Count 查询当前计数值
+func (s *CounterService) Count(ctx context.Context) (*big.Int, error) {
+	results, err := s.contract.Call(ctx, "getCount")
+	if err != nil {
+		return nil, err
+	}
+	return results[0].(*big.Int), nil
+}
+
+// Owner 查询合约所有者
+func (s *CounterService) Owner(ctx context.Context) (common.Address, error) {
+	results, err := s.contract.Call(ctx, "getOwner")
+	if err != nil {
+		return common.Address{}, err
+	}
+	return results[0].(common.Address), nil
+}
+
+// Increment 发送增加计数的交易
+func (s *CounterService) Increment(ctx context.Context, amount *big.Int, req TxRequest) (*Transaction, error) {
+	return s.transact(ctx, req, "increment", amount)
+}
+
+// Decrement 发送减少计数的交易，计数值小于amount时合约会回滚，在估算gas时即返回 ErrReverted
+func (s *CounterService) Decrement(ctx context.Context, amount *big.Int, req TxRequest) (*Transaction, error) {
+	return s.transact(ctx, req, "decrement", amount)
+}
+
+// Reset 发送计数清零的交易，仅限所有者
+func (s *CounterService) Reset(ctx context.Context, req TxRequest) (*Transaction, error) {
+	if err := s.checkOwner(ctx); err != nil {
+		return nil, err
+	}
+	return s.transact(ctx, req, "reset")
+}
+
+// TransferOwnership 发送转移所有权的交易，仅限所有者
+func (s *CounterService) TransferOwnership(ctx context.Context, newOwner common.Address, req TxRequest) (*Transaction, error) {
+	if err := s.checkOwner(ctx); err != nil {
+		return nil, err
+	}
+	return s.transact(ctx, req, "transferOwnership", newOwner)
+}
+
+// Destroy 发送销毁合约的交易，仅限所有者
+func (s *CounterService) Destroy(ctx context.Context, req TxRequest) (*Transaction, error) {
+	if err := s.checkOwner(ctx); err != nil {
+		return nil, err
+	}
+	return s.transact(ctx, req, "destroy")
+}
+
+// transact 使用服务的签名账户发送交易，req中的gas、nonce和费用含义同 Client.Send
+func (s *CounterService) transact(ctx context.Context, req TxRequest, method string, args ...interface{}) (*Transaction, error) {
+	if s.signer == nil {
+		return nil, errNoSigner
+	}
+	return s.contract.TransactWith(ctx, s.signer, req, method, args...)
+}
+
+// checkOwner 检查签名账户是否为合约所有者，避免发送注定回滚的交易
+func (s *CounterService) checkOwner(ctx context.Context) error {
+	if s.signer == nil {
+		return errNoSigner
+	}
+
+	owner, err := s.Owner(ctx)
+	if err != nil {
+		return err
+	}
+	if owner != s.signer.Address() {
+		return fmt.Errorf("%w: 签名账户 %s, 合约所有者 %s", ErrNotOwner, s.signer.Address().Hex(), owner.Hex())
+	}
+	return nil
+}
+
+// WaitForResult 等待交易确认并解码计数器事件
+// 交易执行失败时同时返回结果和带回滚原因的错误
+func (s *CounterService) WaitForResult(ctx context.Context, txHash string) (*CounterResult, error) {
+	receipt, err := s.contract.client.WaitForTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return s.result(ctx, receipt)
+}
+
+// Result 查询已确认交易的执行结果，交易未确认时返回 ErrNotFound
+func (s *CounterService) Result(ctx context.Context, txHash string) (*CounterResult, error) {
+	receipt, err := s.contract.client.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return s.result(ctx, receipt)
+}
+
+// result 将收据转换为执行结果，失败的交易附带回滚原因
+func (s *CounterService) result(ctx context.Context, receipt *types.Receipt) (*CounterResult, error) {
+	result, err := s.ParseReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return result, nil
+	}
+
+	revert, err := s.contract.client.RevertReason(ctx, result.TxHash)
+	if err != nil {
+		return result, fmt.Errorf("%w: 交易 %s 执行失败", ErrReverted, result.TxHash)
+	}
+	return result, revert
+}
+
+// ParseReceipt 从交易收据中解码本合约产生的计数器事件
+func (s *CounterService) ParseReceipt(receipt *types.Receipt) (*CounterResult, error) {
+	events, err := s.contract.DecodeReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CounterResult{
+		TxHash:      receipt.TxHash.Hex(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		Status:      receipt.Status,
+		GasUsed:     receipt.GasUsed,
+		Events:      make([]*CounterEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		counterEvent, err := newCounterEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, counterEvent)
+	}
+	return result, nil
+}
+
+// Events 查询 [from, to] 区块范围内的计数器事件，to为nil时查询到最新区块
+func (s *CounterService) Events(ctx context.Context, from uint64, to *big.Int) ([]*CounterEvent, error) {
+	events, err := s.contract.Events(ctx, from, to, counterEvents...)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := make([]*CounterEvent, 0, len(events))
+	for _, event := range events {
+		counterEvent, err := newCounterEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, counterEvent)
+	}
+	return decoded, nil
+}
+
+// newCounterEvent 将按ABI解码的事件转换为计数器事件
+func newCounterEvent(event *Event) (*CounterEvent, error) {
+	newCount, ok := event.Args["newCount"].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("事件 %s 缺少 newCount 参数", event.Name)
+	}
+	by, ok := event.Args["by"].(common.Address)
+	if !ok {
+		return nil, fmt.Errorf("事件 %s 缺少 by 参数", event.Name)
+	}
+
+	return &CounterEvent{
+		Name:        event.Name,
+		NewCount:    newCount,
+		By:          by,
+		BlockNumber: event.BlockNumber,
+		TxHash:      event.TxHash,
+		LogIndex:    event.LogIndex,
+	}, nil
+}
@@ -0,0 +1,236 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"go-eth-backend/internal/pkg/config"
+)
+
+const (
+	counterABIPath = "../../../contracts/build/Counter.abi"
+	counterBinPath = "../../../contracts/build/contracts_Counter_sol_SimpleCounter.bin"
+)
+
+// 显式指定gas上限跳过估算，注定回滚的交易也会被发送上链
+var (
+	deployGas = TxRequest{GasLimit: 3000000}
+	callGas   = TxRequest{GasLimit: 200000}
+)
+
+// deployCounter 在模拟链上部署计数器合约，返回使用signer发送交易的服务
+func deployCounter(t *testing.T, client *Client, backend *simulated.Backend, signer Signer) *CounterService {
+	t.Helper()
+	ctx := context.Background()
+
+	contractABI, err := LoadABI(counterABIPath)
+	if err != nil {
+		t.Fatalf("加载ABI失败: %v", err)
+	}
+	bin, err := os.ReadFile(counterBinPath)
+	if err != nil {
+		t.Fatalf("读取字节码失败: %v", err)
+	}
+
+	contract, tx, err := DeployContract(ctx, client, signer, contractABI, common.FromHex(strings.TrimSpace(string(bin))), deployGas)
+	if err != nil {
+		t.Fatalf("部署合约失败: %v", err)
+	}
+	backend.Commit()
+
+	receipt, err := client.GetTransactionReceipt(ctx, tx.Hash)
+	if err != nil {
+		t.Fatalf("获取部署收据失败: %v", err)
+	}
+	if receipt.Status != 1 {
+		t.Fatalf("部署交易执行失败")
+	}
+
+	service, err := NewCounterService(client, contract.Address(), contractABI, signer)
+	if err != nil {
+		t.Fatalf("创建CounterService失败: %v", err)
+	}
+	return service
+}
+
+func TestCounterServiceIncrementDecrement(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	counter := deployCounter(t, client, backend, signer)
+
+	tx, err := counter.Increment(ctx, big.NewInt(5), callGas)
+	if err != nil {
+		t.Fatalf("Increment失败: %v", err)
+	}
+	backend.Commit()
+
+	result, err := counter.WaitForResult(ctx, tx.Hash)
+	if err != nil {
+		t.Fatalf("等待Increment结果失败: %v", err)
+	}
+	if len(result.Events) != 1 {
+		t.Fatalf("事件数 = %d, want 1", len(result.Events))
+	}
+	event := result.Events[0]
+	if event.Name != CounterEventIncremented || event.NewCount.Int64() != 5 || event.By != signer.Address() {
+		t.Errorf("事件 = %+v, want CountIncremented newCount=5 by=%s", event, signer.Address().Hex())
+	}
+
+	tx, err = counter.Decrement(ctx, big.NewInt(2), callGas)
+	if err != nil {
+		t.Fatalf("Decrement失败: %v", err)
+	}
+	backend.Commit()
+
+	result, err = counter.WaitForResult(ctx, tx.Hash)
+	if err != nil {
+		t.Fatalf("等待Decrement结果失败: %v", err)
+	}
+	if len(result.Events) != 1 || result.Events[0].Name != CounterEventDecremented || result.Events[0].NewCount.Int64() != 3 {
+		t.Errorf("事件 = %+v, want CountDecremented newCount=3", result.Events)
+	}
+
+	count, err := counter.Count(ctx)
+	if err != nil {
+		t.Fatalf("查询计数失败: %v", err)
+	}
+	if count.Int64() != 3 {
+		t.Errorf("count = %s, want 3", count)
+	}
+
+	events, err := counter.Events(ctx, 0, nil)
+	if err != nil {
+		t.Fatalf("查询事件失败: %v", err)
+	}
+	if len(events) != 2 || events[0].Name != CounterEventIncremented || events[1].Name != CounterEventDecremented {
+		t.Errorf("历史事件 = %+v, want CountIncremented, CountDecremented", events)
+	}
+}
+
+func TestCounterServiceResetByOwner(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	counter := deployCounter(t, client, backend, signer)
+
+	if _, err := counter.Increment(ctx, big.NewInt(7), callGas); err != nil {
+		t.Fatalf("Increment失败: %v", err)
+	}
+	backend.Commit()
+
+	tx, err := counter.Reset(ctx, callGas)
+	if err != nil {
+		t.Fatalf("Reset失败: %v", err)
+	}
+	backend.Commit()
+
+	result, err := counter.Result(ctx, tx.Hash)
+	if err != nil {
+		t.Fatalf("查询Reset结果失败: %v", err)
+	}
+	if len(result.Events) != 1 || result.Events[0].Name != CounterEventReset || result.Events[0].NewCount.Sign() != 0 {
+		t.Errorf("事件 = %+v, want CountReset newCount=0", result.Events)
+	}
+}
+
+func TestCounterServiceDecrementBelowZero(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	counter := deployCounter(t, client, backend, signer)
+
+	// 交易上链后执行失败，结果附带回滚原因
+	tx, err := counter.Decrement(ctx, big.NewInt(1), callGas)
+	if err != nil {
+		t.Fatalf("Decrement失败: %v", err)
+	}
+	backend.Commit()
+
+	result, err := counter.WaitForResult(ctx, tx.Hash)
+	var revert *RevertError
+	if !errors.As(err, &revert) || !errors.Is(err, ErrReverted) {
+		t.Fatalf("err = %v, want *RevertError", err)
+	}
+	if revert.Reason != "Cannot decrement below zero" {
+		t.Errorf("回滚原因 = %q", revert.Reason)
+	}
+	if result == nil || result.Status != 0 || len(result.Events) != 0 {
+		t.Errorf("结果 = %+v, want status=0 且无事件", result)
+	}
+}
+
+func TestCounterServiceOwnerPreflight(t *testing.T) {
+	ctx := context.Background()
+	client, backend, owner := newSimulatedClient(t, config.NetworkConfig{})
+	counter := deployCounter(t, client, backend, owner)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	other := &PrivateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+	asOther, err := NewCounterService(client, counter.Address(), counter.contract.ABI(), other)
+	if err != nil {
+		t.Fatalf("创建CounterService失败: %v", err)
+	}
+
+	// 非所有者的调用在发送前被拒绝：other账户没有余额，若交易被发送会返回余额不足
+	if _, err := asOther.Reset(ctx, callGas); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Reset err = %v, want ErrNotOwner", err)
+	}
+	if _, err := asOther.TransferOwnership(ctx, other.Address(), callGas); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("TransferOwnership err = %v, want ErrNotOwner", err)
+	}
+	if _, err := asOther.Destroy(ctx, callGas); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("Destroy err = %v, want ErrNotOwner", err)
+	}
+
+	tx, err := counter.TransferOwnership(ctx, other.Address(), callGas)
+	if err != nil {
+		t.Fatalf("TransferOwnership失败: %v", err)
+	}
+	backend.Commit()
+	if _, err := counter.WaitForResult(ctx, tx.Hash); err != nil {
+		t.Fatalf("等待TransferOwnership结果失败: %v", err)
+	}
+
+	newOwner, err := counter.Owner(ctx)
+	if err != nil {
+		t.Fatalf("查询所有者失败: %v", err)
+	}
+	if newOwner != other.Address() {
+		t.Fatalf("owner = %s, want %s", newOwner.Hex(), other.Address().Hex())
+	}
+
+	// 所有权转移后，原所有者也被拒绝
+	if _, err := counter.Reset(ctx, callGas); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("原所有者Reset err = %v, want ErrNotOwner", err)
+	}
+}
+
+func TestCounterServiceReadOnly(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	deployed := deployCounter(t, client, backend, signer)
+
+	counter, err := NewCounterService(client, deployed.Address(), deployed.contract.ABI(), nil)
+	if err != nil {
+		t.Fatalf("创建CounterService失败: %v", err)
+	}
+
+	count, err := counter.Count(ctx)
+	if err != nil {
+		t.Fatalf("查询计数失败: %v", err)
+	}
+	if count.Sign() != 0 {
+		t.Errorf("count = %s, want 0", count)
+	}
+	if _, err := counter.Increment(ctx, big.NewInt(1), callGas); err == nil {
+		t.Error("未配置签名账户时Increment应失败")
+	}
+}
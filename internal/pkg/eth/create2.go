@@ -0,0 +1,169 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultCreate2Deployer 标准的确定性部署代理（Arachnid/deterministic-deployment-proxy）
+// 通过预签名交易部署，在主网、各测试网和大多数EVM链上地址相同；调用数据为 salt(32字节) ++ 创建代码
+var DefaultCreate2Deployer = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+
+// Create2Address 计算通过CREATE2部署的合约地址：keccak256(0xff ++ deployer ++ salt ++ keccak256(initCode))[12:]
+func Create2Address(deployer common.Address, salt common.Hash, initCode []byte) common.Address {
+	return crypto.CreateAddress2(deployer, salt, crypto.Keccak256(initCode))
+}
+
+// SaltFromString 由可读的标签（如 "SimpleCounter-v1"）生成salt
+func SaltFromString(label string) common.Hash {
+	return crypto.Keccak256Hash([]byte(label))
+}
+
+// InitCode 拼接合约创建代码：字节码 ++ ABI编码的构造函数参数，参数转换规则同 ConvertArgs
+func InitCode(contractABI abi.ABI, bytecode []byte, args ...interface{}) ([]byte, error) {
+	converted, err := ConvertArgs(contractABI.Constructor.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("构造函数%w", err)
+	}
+	input, err := contractABI.Pack("", converted...)
+	if err != nil {
+		return nil, fmt.Errorf("编码构造函数参数失败: %w", err)
+	}
+	return append(append([]byte{}, bytecode...), input...), nil
+}
+
+// Deployer 通过CREATE2工厂确定性地部署合约：相同的工厂、salt和创建代码在任何链上都得到相同的地址
+type Deployer struct {
+	client   *Client
+	factory  common.Address
+	manifest *Manifest
+}
+
+// NewDeployer 创建CREATE2部署器，工厂地址取自网络配置的 create2_deployer，未配置时使用 DefaultCreate2Deployer
+// manifest 为nil时不记录部署结果
+func NewDeployer(client *Client, manifest *Manifest) *Deployer {
+	factory := DefaultCreate2Deployer
+	if configured := client.NetworkConfig().Create2Deployer; configured != "" {
+		factory = common.HexToAddress(configured)
+	}
+	return &Deployer{client: client, factory: factory, manifest: manifest}
+}
+
+// Factory 返回CREATE2工厂地址
+func (d *Deployer) Factory() common.Address {
+	return d.factory
+}
+
+// Predict 在发送交易前计算合约将被部署到的地址
+func (d *Deployer) Predict(initCode []byte, salt common.Hash) common.Address {
+	return Create2Address(d.factory, salt, initCode)
+}
+
+// Deploy 通过CREATE2工厂部署合约并等待交易确认，name为部署清单中的合约名称
+// 预测地址上已有代码时不发送交易，直接返回部署记录（清单中没有记录时补记，交易哈希为空）；
+// 返回的bool表示本次是否发送了部署交易；部署成功但写入清单失败时同时返回部署记录和错误
+// 注意构造函数中的 msg.sender 是工厂而不是签名账户，依赖部署者身份的合约（如记录owner）应改为通过构造函数参数传入
+func (d *Deployer) Deploy(ctx context.Context, signer Signer, name string, initCode []byte, salt common.Hash, req TxRequest) (*Deployment, bool, error) {
+	address := d.Predict(initCode, salt)
+	deployment := &Deployment{
+		Address:      address.Hex(),
+		BytecodeHash: crypto.Keccak256Hash(initCode).Hex(),
+		Salt:         salt.Hex(),
+		Deployer:     d.factory.Hex(),
+	}
+
+	network, err := d.network(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	exists, err := d.hasCode(ctx, address)
+	if err != nil {
+		return nil, false, err
+	}
+	if exists {
+		if d.manifest == nil {
+			return deployment, false, nil
+		}
+		if recorded, ok := d.manifest.Get(network, name); ok && recorded.Address == deployment.Address {
+			return recorded, false, nil
+		}
+		if err := d.manifest.Record(network, name, deployment); err != nil {
+			return nil, false, err
+		}
+		return deployment, false, nil
+	}
+
+	factoryExists, err := d.hasCode(ctx, d.factory)
+	if err != nil {
+		return nil, false, err
+	}
+	if !factoryExists {
+		return nil, false, fmt.Errorf("CREATE2部署工厂 %s 在网络 %s 上不存在", d.factory.Hex(), network)
+	}
+
+	req.To = &d.factory
+	req.Data = append(salt.Bytes(), initCode...)
+	tx, err := d.client.Send(ctx, signer, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	receipt, err := d.client.WaitForTransactionReceipt(ctx, tx.Hash)
+	if err != nil {
+		return nil, false, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		if revert, err := d.client.RevertReason(ctx, tx.Hash); err == nil {
+			return nil, false, fmt.Errorf("部署交易 %s 执行失败: %w", tx.Hash, revert)
+		}
+		return nil, false, fmt.Errorf("%w: 部署交易 %s 执行失败", ErrReverted, tx.Hash)
+	}
+
+	// 工厂在创建失败时会回滚，这里再确认一次，避免工厂实现不同导致记录错误的地址
+	exists, err = d.hasCode(ctx, address)
+	if err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		return nil, false, fmt.Errorf("部署交易 %s 已确认，但预测地址 %s 上没有合约代码", tx.Hash, address.Hex())
+	}
+
+	deployment.TxHash = tx.Hash
+	deployment.BlockNumber = receipt.BlockNumber.Uint64()
+	if d.manifest != nil {
+		if err := d.manifest.Record(network, name, deployment); err != nil {
+			return deployment, true, err
+		}
+	}
+	return deployment, true, nil
+}
+
+// network 返回部署清单中使用的网络名称，客户端不是按网络名称创建时使用链ID
+func (d *Deployer) network(ctx context.Context) (string, error) {
+	if network := d.client.Network(); network != "" {
+		return network, nil
+	}
+	chainID, err := d.client.ChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return chainID.String(), nil
+}
+
+// hasCode 地址上是否有合约代码
+func (d *Deployer) hasCode(ctx context.Context, address common.Address) (bool, error) {
+	ctx, cancel := d.client.withTimeout(ctx, d.client.requestTimeout)
+	defer cancel()
+
+	code, err := d.client.Client.CodeAt(ctx, address, nil)
+	if err != nil {
+		return false, wrapError(err, "查询地址 %s 的合约代码失败", address.Hex())
+	}
+	return len(code) > 0, nil
+}
@@ -0,0 +1,145 @@
+package eth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// create2ProxyCode 确定性部署代理的运行时代码
+var create2ProxyCode = common.FromHex("0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3")
+
+func TestCreate2AddressEIP1014(t *testing.T) {
+	// EIP-1014 中的测试向量
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{"0x0000000000000000000000000000000000000000", "0x00", "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x00", "0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0xdeadbeef00000000000000000000000000000000", "0xfeed000000000000000000000000000000000000", "0x00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x0000000000000000000000000000000000000000", "0x00", "0xdeadbeef", "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e"},
+		{"0x00000000000000000000000000000000deadbeef", "0xcafebabe", "0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x00000000000000000000000000000000deadbeef", "0xcafebabe",
+			"0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			"0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C"},
+		{"0x0000000000000000000000000000000000000000", "0x00", "0x", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+	for _, tt := range tests {
+		salt := common.HexToHash(tt.salt)
+		got := Create2Address(common.HexToAddress(tt.deployer), salt, common.FromHex(tt.initCode))
+		if got.Hex() != tt.want {
+			t.Errorf("Create2Address(%s, %s, %s) = %s, want %s", tt.deployer, tt.salt, tt.initCode, got.Hex(), tt.want)
+		}
+	}
+}
+
+// newCreate2Client 创建创世区块中包含确定性部署代理的模拟链，并在后台持续出块
+func newCreate2Client(t *testing.T) (*Client, *PrivateKeySigner) {
+	t.Helper()
+	client, backend, signer := newSimulatedClientWithAlloc(t, config.NetworkConfig{}, types.GenesisAlloc{
+		DefaultCreate2Deployer: {Code: create2ProxyCode},
+	})
+	// 创世区块不支持PUSH0，先出一个块
+	backend.Commit()
+	mine(t, backend, 10*time.Millisecond)
+	return client, signer
+}
+
+// counterInitCode 返回计数器合约的创建代码
+func counterInitCode(t *testing.T) []byte {
+	t.Helper()
+	contractABI, err := LoadABI(counterABIPath)
+	if err != nil {
+		t.Fatalf("加载ABI失败: %v", err)
+	}
+	bin, err := os.ReadFile(counterBinPath)
+	if err != nil {
+		t.Fatalf("读取字节码失败: %v", err)
+	}
+	initCode, err := InitCode(contractABI, common.FromHex(strings.TrimSpace(string(bin))))
+	if err != nil {
+		t.Fatalf("生成创建代码失败: %v", err)
+	}
+	return initCode
+}
+
+func TestDeployerDeploysAndSkipsExisting(t *testing.T) {
+	ctx := context.Background()
+	client, signer := newCreate2Client(t)
+	manifest, err := LoadManifest(filepath.Join(t.TempDir(), "deployments.json"))
+	if err != nil {
+		t.Fatalf("加载部署清单失败: %v", err)
+	}
+	deployer := NewDeployer(client, manifest)
+
+	initCode := counterInitCode(t)
+	salt := SaltFromString("SimpleCounter-test")
+	predicted := deployer.Predict(initCode, salt)
+
+	deployment, sent, err := deployer.Deploy(ctx, signer, "SimpleCounter", initCode, salt, TxRequest{})
+	if err != nil {
+		t.Fatalf("部署失败: %v", err)
+	}
+	if !sent || deployment.Address != predicted.Hex() || deployment.TxHash == "" || deployment.BlockNumber == 0 {
+		t.Fatalf("部署结果 = %+v, sent = %v, want 部署到 %s", deployment, sent, predicted.Hex())
+	}
+	if recorded, ok := manifest.Get("simulated", "SimpleCounter"); !ok || *recorded != *deployment {
+		t.Errorf("清单记录 = %+v, want %+v", recorded, deployment)
+	}
+
+	// 构造函数中的 msg.sender 是工厂，owner因此是工厂地址
+	contractABI, _ := LoadABI(counterABIPath)
+	owner, err := NewContract(client, predicted, contractABI).Call(ctx, "getOwner")
+	if err != nil {
+		t.Fatalf("查询owner失败: %v", err)
+	}
+	if owner[0].(common.Address) != DefaultCreate2Deployer {
+		t.Errorf("owner = %s, want 工厂地址 %s", owner[0], DefaultCreate2Deployer.Hex())
+	}
+
+	// 再次部署时地址上已有代码，返回清单中的记录且不发送交易
+	nonce, err := client.GetNonce(ctx, signer.Address().Hex())
+	if err != nil {
+		t.Fatalf("查询nonce失败: %v", err)
+	}
+	again, sent, err := deployer.Deploy(ctx, signer, "SimpleCounter", initCode, salt, TxRequest{})
+	if err != nil || sent || *again != *deployment {
+		t.Fatalf("再次部署 = %+v, %v, %v, want 跳过并返回 %+v", again, sent, err, deployment)
+	}
+	if after, _ := client.GetNonce(ctx, signer.Address().Hex()); after != nonce {
+		t.Errorf("跳过部署时发送了交易: nonce %d -> %d", nonce, after)
+	}
+
+	// 清单中没有记录时补记，交易哈希为空
+	fresh, err := LoadManifest(filepath.Join(t.TempDir(), "deployments.json"))
+	if err != nil {
+		t.Fatalf("加载部署清单失败: %v", err)
+	}
+	existing, sent, err := NewDeployer(client, fresh).Deploy(ctx, signer, "SimpleCounter", initCode, salt, TxRequest{})
+	if err != nil || sent || existing.Address != predicted.Hex() || existing.TxHash != "" {
+		t.Fatalf("补记部署 = %+v, %v, %v", existing, sent, err)
+	}
+	if _, ok := fresh.Get("simulated", "SimpleCounter"); !ok {
+		t.Error("地址上已有代码时应补记到清单")
+	}
+}
+
+func TestDeployerRequiresFactory(t *testing.T) {
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	backend.Commit()
+
+	_, _, err := NewDeployer(client, nil).Deploy(context.Background(), signer, "SimpleCounter", counterInitCode(t), SaltFromString("x"), TxRequest{})
+	if err == nil || !strings.Contains(err.Error(), DefaultCreate2Deployer.Hex()) {
+		t.Fatalf("err = %v, want 工厂不存在", err)
+	}
+}
@@ -0,0 +1,259 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// eth包对外暴露的错误类型
+// 所有访问节点的方法返回的错误都会按节点返回的JSON-RPC错误码和消息归类，
+// 调用方可以通过 errors.Is / errors.As 判断失败原因，同时保留底层错误
+var (
+	// ErrNotFound 区块、交易或收据不存在
+	ErrNotFound = errors.New("未找到")
+	// ErrInsufficientFunds 账户余额不足以支付 gas*price + value
+	ErrInsufficientFunds = errors.New("余额不足")
+	// ErrNonceTooLow 交易nonce低于账户当前nonce
+	ErrNonceTooLow = errors.New("nonce过低")
+	// ErrUnderpriced 交易费用过低（低于节点最低价格、低于baseFee或替换交易加价不足）
+	ErrUnderpriced = errors.New("交易费用过低")
+	// ErrRPCUnavailable 节点不可达、超时、限流、返回5xx或尚未完成交易索引
+	ErrRPCUnavailable = errors.New("RPC节点不可用")
+	// ErrChainIDMismatch 节点返回的链ID与配置不一致
+	ErrChainIDMismatch = errors.New("链ID不匹配")
+	// ErrReverted 合约执行被回滚，具体原因见 *RevertError
+	ErrReverted = errors.New("合约执行被回滚")
+	// ErrNotOwner 签名账户不是合约所有者，仅限所有者的调用在发送前即被拒绝
+	ErrNotOwner = errors.New("不是合约所有者")
+)
+
+// JSON-RPC错误码
+const (
+	// rpcCodeExecutionReverted eth_call/eth_estimateGas执行被回滚
+	rpcCodeExecutionReverted = 3
+	// rpcCodeLimitExceeded 请求超出节点限额
+	rpcCodeLimitExceeded = -32005
+	// rpcCodeMethodNotFound 节点不支持该方法
+	rpcCodeMethodNotFound = -32601
+)
+
+// RevertError 合约执行回滚错误，携带回滚原因
+// Reason 为 Error(string) 的消息、Panic(uint256) 错误码的含义或自定义错误的可读形式
+type RevertError struct {
+	Reason    string        // 解码后的回滚原因，无法解码时为空
+	PanicCode *big.Int      // Panic(uint256) 的错误码，非Panic时为nil
+	ErrorName string        // 自定义错误名称，非自定义错误时为空
+	ErrorArgs []interface{} // 自定义错误参数
+	Data      hexutil.Bytes // 节点返回的原始回滚数据
+	err       error
+}
+
+// Error 实现error接口
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return ErrReverted.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrReverted.Error(), e.Reason)
+}
+
+// Unwrap 支持 errors.Is(err, ErrReverted)，并保留节点返回的原始错误
+func (e *RevertError) Unwrap() []error {
+	if e.err == nil {
+		return []error{ErrReverted}
+	}
+	return []error{ErrReverted, e.err}
+}
+
+// wrapError 为节点返回的错误附加描述，并归类为对应的哨兵错误
+func wrapError(err error, format string, args ...any) error {
+	return wrapErrorWith(err, nil, format, args...)
+}
+
+// wrapErrorWith 与wrapError相同，lookup非nil时用于解码回滚数据中的自定义错误
+func wrapErrorWith(err error, lookup errorLookup, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+
+	switch kind := classifyError(err, lookup).(type) {
+	case nil:
+		return fmt.Errorf("%s: %w", msg, err)
+	case *RevertError:
+		return fmt.Errorf("%s: %w", msg, kind)
+	default:
+		return fmt.Errorf("%s: %w: %w", msg, kind, err)
+	}
+}
+
+// classifyError 根据JSON-RPC错误码和消息判断错误类别，无法归类或已归类时返回nil
+func classifyError(err error, lookup errorLookup) error {
+	for _, known := range []error{ErrNotFound, ErrInsufficientFunds, ErrNonceTooLow, ErrUnderpriced, ErrRPCUnavailable, ErrChainIDMismatch, ErrReverted} {
+		if errors.Is(err, known) {
+			return nil
+		}
+	}
+
+	if errors.Is(err, ethereum.NotFound) {
+		return ErrNotFound
+	}
+	if revert := asRevertError(err, lookup); revert != nil {
+		return revert
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case isIndexing(err):
+		// 节点尚未完成交易索引是暂时状态，稍后重试即可查到，不能当作交易不存在
+		return ErrRPCUnavailable
+	case strings.Contains(msg, "insufficient funds"):
+		return ErrInsufficientFunds
+	case strings.Contains(msg, "nonce too low"):
+		return ErrNonceTooLow
+	case strings.Contains(msg, "underpriced"),
+		strings.Contains(msg, "fee cap less than block base fee"),
+		strings.Contains(msg, "max fee per gas less than block base fee"),
+		strings.Contains(msg, "fee cap too low"):
+		return ErrUnderpriced
+	}
+
+	if isUnavailable(err) {
+		return ErrRPCUnavailable
+	}
+	return nil
+}
+
+// asRevertError 从节点错误中提取并解码回滚数据
+func asRevertError(err error, lookup errorLookup) *RevertError {
+	var rpcErr rpc.Error
+	isRevertCode := errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcCodeExecutionReverted
+	if !isRevertCode && !strings.Contains(err.Error(), "execution reverted") {
+		return nil
+	}
+
+	revert := &RevertError{err: err}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			revert.Data, _ = hexutil.Decode(data)
+		}
+	}
+	revert.decode(lookup)
+
+	return revert
+}
+
+// isUnavailable 判断是否为连接失败、超时、限流或服务端错误
+func isUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcCodeLimitExceeded
+}
+
+// isRejected 判断发送交易的错误是否为节点明确拒绝（JSON-RPC错误响应），此时交易没有进入交易池
+// 超时、连接中断、限流和5xx无法确定节点是否已接收交易；"交易已存在"说明交易已在交易池中，均返回false
+func isRejected(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || isUnavailable(err) {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	return !strings.Contains(msg, "already known") && !strings.Contains(msg, "known transaction")
+}
+
+// isIndexing 判断错误是否为节点尚未完成交易索引，稍后重试即可查到
+func isIndexing(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "transaction indexing is in progress")
+}
+
+// rangeLimitMessages 各节点服务商对 eth_getLogs 查询范围或结果数量超限的错误消息
+var rangeLimitMessages = []string{
+	"query returned more than", // Infura
+	"response size exceeded",   // Alchemy
+	"block range",              // 多数节点: "block range is too large" / "exceed maximum block range"
+	"range is too large",
+	"range too large",
+	"is limited to", // QuickNode: "eth_getLogs is limited to a 10,000 range"
+	"too many results",
+}
+
+// isRangeLimit 判断错误是否为 eth_getLogs 查询范围超出节点限制，缩小范围后可重试
+func isRangeLimit(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, limit := range rangeLimitMessages {
+		if strings.Contains(msg, limit) {
+			return true
+		}
+	}
+	return false
+}
+
+// batchLimitMessages 各节点服务商拒绝过大批量请求的错误消息
+var batchLimitMessages = []string{
+	"batch too large", // geth
+	"batch size",      // "batch size too large" / "batch size exceeds limit"
+	"batch limit",
+	"too many requests in batch",
+	"cannot unmarshal object into go value of type []*rpc.jsonrpcmessage", // 对整批只返回单个错误对象
+}
+
+// isBatchLimit 判断错误是否为批量请求超出节点限制，拆小后可重试
+func isBatchLimit(err error) bool {
+	if errors.Is(err, rpc.ErrMissingBatchResponse) {
+		// geth只对批量请求中第一个调用返回错误，其余调用没有响应
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusRequestEntityTooLarge {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, limit := range batchLimitMessages {
+		if strings.Contains(msg, limit) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMethodNotFound 判断错误是否为节点不支持该RPC方法
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcCodeMethodNotFound {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") ||
+		strings.Contains(msg, "does not exist/is not available") ||
+		strings.Contains(msg, "unsupported method")
+}
@@ -0,0 +1,120 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testRPCError 模拟节点返回的JSON-RPC错误
+type testRPCError struct {
+	code    int
+	message string
+	data    interface{}
+}
+
+func (e *testRPCError) Error() string          { return e.message }
+func (e *testRPCError) ErrorCode() int         { return e.code }
+func (e *testRPCError) ErrorData() interface{} { return e.data }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"not found", ethereum.NotFound, ErrNotFound},
+		{"wrapped not found", fmt.Errorf("查询失败: %w", ethereum.NotFound), ErrNotFound},
+		{"indexing", &testRPCError{code: -32000, message: "transaction indexing is in progress"}, ErrRPCUnavailable},
+		{"insufficient funds", &testRPCError{code: -32000, message: "insufficient funds for gas * price + value: balance 0"}, ErrInsufficientFunds},
+		{"nonce too low", &testRPCError{code: -32000, message: "nonce too low: next nonce 5, tx nonce 3"}, ErrNonceTooLow},
+		{"replacement underpriced", errors.New("replacement transaction underpriced"), ErrUnderpriced},
+		{"below base fee", errors.New("max fee per gas less than block base fee: address 0x01"), ErrUnderpriced},
+		{"fee cap too low", errors.New("transaction fee cap too low"), ErrUnderpriced},
+		{"limit exceeded", &testRPCError{code: rpcCodeLimitExceeded, message: "limit exceeded"}, ErrRPCUnavailable},
+		{"http 429", rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}, ErrRPCUnavailable},
+		{"http 503", rpc.HTTPError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}, ErrRPCUnavailable},
+		{"http 400", rpc.HTTPError{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"}, nil},
+		{"deadline", context.DeadlineExceeded, ErrRPCUnavailable},
+		{"connection refused", fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED), ErrRPCUnavailable},
+		{"already classified", fmt.Errorf("发送交易失败: %w", ErrNonceTooLow), nil},
+		{"unknown", errors.New("invalid argument 0: hex string has length 3"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err, nil); got != tt.want {
+				t.Errorf("classifyError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyErrorRevert(t *testing.T) {
+	err := &testRPCError{code: rpcCodeExecutionReverted, message: "execution reverted"}
+	revert, ok := classifyError(err, nil).(*RevertError)
+	if !ok {
+		t.Fatalf("错误码3应归类为 *RevertError")
+	}
+	if !errors.Is(revert, ErrReverted) || !errors.Is(revert, err) {
+		t.Error("RevertError 应同时匹配 ErrReverted 和原始错误")
+	}
+
+	if _, ok := classifyError(errors.New("execution reverted"), nil).(*RevertError); !ok {
+		t.Error("没有错误码的 execution reverted 消息也应归类为 *RevertError")
+	}
+}
+
+func TestWrapErrorKeepsCause(t *testing.T) {
+	cause := &testRPCError{code: -32000, message: "nonce too low"}
+	err := wrapError(cause, "发送交易 %d 失败", 1)
+
+	if !errors.Is(err, ErrNonceTooLow) {
+		t.Errorf("errors.Is(%v, ErrNonceTooLow) = false", err)
+	}
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32000 {
+		t.Errorf("包装后应保留原始JSON-RPC错误, got %v", err)
+	}
+
+	// 已归类的错误再次包装时不重复附加类别
+	twice := wrapError(err, "重试失败")
+	if want := "重试失败: " + err.Error(); twice.Error() != want {
+		t.Errorf("再次包装 = %q, want %q", twice.Error(), want)
+	}
+}
+
+func TestLimitErrors(t *testing.T) {
+	tests := []struct {
+		err                    error
+		rangeLimit, batchLimit bool
+		methodNotFound         bool
+	}{
+		{err: errors.New("query returned more than 10000 results"), rangeLimit: true},
+		{err: errors.New("exceed maximum block range: 5000"), rangeLimit: true},
+		{err: errors.New("batch too large"), batchLimit: true},
+		{err: rpc.ErrMissingBatchResponse, batchLimit: true},
+		{err: rpc.HTTPError{StatusCode: http.StatusRequestEntityTooLarge}, batchLimit: true},
+		{err: &testRPCError{code: rpcCodeMethodNotFound, message: "the method eth_getBlockReceipts does not exist/is not available"}, methodNotFound: true},
+		{err: errors.New("Unsupported method: eth_getBlockReceipts"), methodNotFound: true},
+		{err: errors.New("header not found")},
+	}
+
+	for _, tt := range tests {
+		if got := isRangeLimit(tt.err); got != tt.rangeLimit {
+			t.Errorf("isRangeLimit(%v) = %v, want %v", tt.err, got, tt.rangeLimit)
+		}
+		if got := isBatchLimit(tt.err); got != tt.batchLimit {
+			t.Errorf("isBatchLimit(%v) = %v, want %v", tt.err, got, tt.batchLimit)
+		}
+		if got := isMethodNotFound(tt.err); got != tt.methodNotFound {
+			t.Errorf("isMethodNotFound(%v) = %v, want %v", tt.err, got, tt.methodNotFound)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go-eth-backend/internal/pkg/config"
+)
+
+const (
+	// DefaultMaxFeeMultiplier 未配置时 maxFeePerGas 相对 baseFee 的默认倍数
+	DefaultMaxFeeMultiplier = 2.0
+	// DefaultGasLimitMultiplier 未配置时估算gas的默认安全余量倍数
+	DefaultGasLimitMultiplier = 1.2
+)
+
+// FeeParams 交易费用参数
+// GasFeeCap 非空时表示EIP-1559动态费用交易，否则为使用GasPrice的legacy交易
+type FeeParams struct {
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// IsDynamic 是否为EIP-1559动态费用
+func (p *FeeParams) IsDynamic() bool {
+	return p.GasFeeCap != nil
+}
+
+// feeConfig 返回补齐默认值后的费用配置
+func (c *Client) feeConfig() config.FeeConfig {
+	fees := c.networkConfig.Fees
+	if fees.Mode == "" {
+		fees.Mode = config.FeeModeAuto
+	}
+	if fees.MaxFeeMultiplier <= 0 {
+		fees.MaxFeeMultiplier = DefaultMaxFeeMultiplier
+	}
+	if fees.GasLimitMultiplier <= 0 {
+		fees.GasLimitMultiplier = DefaultGasLimitMultiplier
+	}
+	return fees
+}
+
+// SuggestFees 根据网络费用配置给出交易费用
+// auto模式下最新区块含baseFee时使用EIP-1559，否则退回legacy gasPrice
+func (c *Client) SuggestFees(ctx context.Context) (*FeeParams, error) {
+	fees := c.feeConfig()
+
+	switch fees.Mode {
+	case config.FeeModeLegacy:
+		return c.suggestLegacyFees(ctx)
+	case config.FeeModeAuto, config.FeeModeEIP1559:
+	default:
+		return nil, fmt.Errorf("未知的费用模式: %s", fees.Mode)
+	}
+
+	header, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, wrapError(err, "获取最新区块头失败")
+	}
+	if header.BaseFee == nil {
+		if fees.Mode == config.FeeModeEIP1559 {
+			return nil, fmt.Errorf("当前链不支持EIP-1559: 最新区块没有baseFee")
+		}
+		return c.suggestLegacyFees(ctx)
+	}
+
+	tipCap, err := c.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, wrapError(err, "获取建议小费失败")
+	}
+
+	return &FeeParams{
+		GasTipCap: tipCap,
+		GasFeeCap: maxFeePerGas(header.BaseFee, tipCap, fees.MaxFeeMultiplier),
+	}, nil
+}
+
+// latestBaseFee 获取最新区块的baseFee，链不支持EIP-1559时返回错误
+func (c *Client) latestBaseFee(ctx context.Context) (*big.Int, error) {
+	header, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, wrapError(err, "获取最新区块头失败")
+	}
+	if header.BaseFee == nil {
+		return nil, fmt.Errorf("当前链不支持EIP-1559: 最新区块没有baseFee")
+	}
+	return header.BaseFee, nil
+}
+
+// suggestLegacyFees 获取legacy交易的gasPrice
+func (c *Client) suggestLegacyFees(ctx context.Context) (*FeeParams, error) {
+	gasPrice, err := c.Client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, wrapError(err, "获取gas价格失败")
+	}
+
+	return &FeeParams{GasPrice: gasPrice}, nil
+}
+
+// maxFeePerGas 计算 baseFee * multiplier + tipCap
+func maxFeePerGas(baseFee, tipCap *big.Int, multiplier float64) *big.Int {
+	// 以千分之一精度换算倍数，避免浮点运算带来的精度问题
+	scaled := new(big.Int).Mul(baseFee, big.NewInt(int64(multiplier*1000)))
+	scaled.Div(scaled, big.NewInt(1000))
+	return scaled.Add(scaled, tipCap)
+}
+
+// newTransaction 根据费用参数构建EIP-1559、EIP-2930或legacy交易
+func newTransaction(chainID *big.Int, nonce uint64, to *common.Address, value *big.Int, gasLimit uint64, data []byte, accessList types.AccessList, fees *FeeParams) *types.Transaction {
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	if fees.IsDynamic() {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasTipCap:  fees.GasTipCap,
+			GasFeeCap:  fees.GasFeeCap,
+			Gas:        gasLimit,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: accessList,
+		})
+	}
+
+	if len(accessList) > 0 {
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasPrice:   fees.GasPrice,
+			Gas:        gasLimit,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: accessList,
+		})
+	}
+
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: fees.GasPrice,
+		Gas:      gasLimit,
+		To:       to,
+		Value:    value,
+		Data:     data,
+	})
+}
@@ -0,0 +1,119 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go-eth-backend/internal/pkg/config"
+)
+
+func TestMaxFeePerGas(t *testing.T) {
+	got := maxFeePerGas(big.NewInt(1000), big.NewInt(7), 1.5)
+	if got.Cmp(big.NewInt(1507)) != 0 {
+		t.Fatalf("maxFeePerGas = %s, want 1507", got)
+	}
+}
+
+func TestSendTransactionDynamicFee(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{
+		Fees: config.FeeConfig{Mode: config.FeeModeAuto, MaxFeeMultiplier: 3},
+	})
+
+	head, err := client.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatalf("获取区块头失败: %v", err)
+	}
+	tip, err := client.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		t.Fatalf("获取小费失败: %v", err)
+	}
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	hash, err := client.SendTransaction(ctx, signer, to.Hex(), big.NewInt(1e15))
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	backend.Commit()
+
+	tx, _, err := client.GetTransactionByHash(ctx, hash)
+	if err != nil {
+		t.Fatalf("获取交易失败: %v", err)
+	}
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Fatalf("交易类型 = %d, want %d", tx.Type(), types.DynamicFeeTxType)
+	}
+	if tx.GasTipCap().Cmp(tip) != 0 {
+		t.Errorf("GasTipCap = %s, want %s", tx.GasTipCap(), tip)
+	}
+	if want := maxFeePerGas(head.BaseFee, tip, 3); tx.GasFeeCap().Cmp(want) != 0 {
+		t.Errorf("GasFeeCap = %s, want %s", tx.GasFeeCap(), want)
+	}
+
+	receipt, err := client.GetTransactionReceipt(ctx, hash)
+	if err != nil {
+		t.Fatalf("获取收据失败: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("交易执行失败, status = %d", receipt.Status)
+	}
+
+	balance, err := client.GetBalance(ctx, to.Hex())
+	if err != nil {
+		t.Fatalf("获取余额失败: %v", err)
+	}
+	if balance.Cmp(big.NewInt(1e15)) != 0 {
+		t.Errorf("接收方余额 = %s, want 1e15", balance)
+	}
+}
+
+func TestSendTransactionLegacy(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{
+		Fees: config.FeeConfig{Mode: config.FeeModeLegacy},
+	})
+
+	gasPrice, err := client.Client.SuggestGasPrice(ctx)
+	if err != nil {
+		t.Fatalf("获取gas价格失败: %v", err)
+	}
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	hash, err := client.SendTransaction(ctx, signer, to.Hex(), big.NewInt(1e15))
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	backend.Commit()
+
+	tx, _, err := client.GetTransactionByHash(ctx, hash)
+	if err != nil {
+		t.Fatalf("获取交易失败: %v", err)
+	}
+	if tx.Type() != types.LegacyTxType {
+		t.Fatalf("交易类型 = %d, want %d", tx.Type(), types.LegacyTxType)
+	}
+	if tx.GasPrice().Cmp(gasPrice) != 0 {
+		t.Errorf("GasPrice = %s, want %s", tx.GasPrice(), gasPrice)
+	}
+
+	receipt, err := client.GetTransactionReceipt(ctx, hash)
+	if err != nil {
+		t.Fatalf("获取收据失败: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("交易执行失败, status = %d", receipt.Status)
+	}
+}
+
+func TestSuggestFeesUnknownMode(t *testing.T) {
+	client, _, _ := newSimulatedClient(t, config.NetworkConfig{
+		Fees: config.FeeConfig{Mode: "turbo"},
+	})
+
+	if _, err := client.SuggestFees(context.Background()); err == nil {
+		t.Fatal("未知费用模式应返回错误")
+	}
+}
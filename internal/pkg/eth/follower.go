@@ -0,0 +1,164 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultFollowerDepth 默认保留的最近区块数，即能够处理的最大重组深度
+const DefaultFollowerDepth = 64
+
+// ErrReorgTooDeep 重组深度超过跟随器保留的区块数，无法找到共同祖先
+var ErrReorgTooDeep = errors.New("链重组深度超过保留的区块数")
+
+// BlockSource 区块跟随器读取区块的来源，*Client 实现了该接口
+type BlockSource interface {
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*JSONBlock, error)
+}
+
+// ReorgEvent 链重组事件
+type ReorgEvent struct {
+	Depth          int          // 被撤销的区块数
+	CommonAncestor uint64       // 新旧链的共同祖先区块号
+	Hashes         []string     // 被撤销的区块哈希，从高到低
+	Retracted      []*JSONBlock // 被撤销的区块，从高到低；消费者应撤销这些区块上的数据
+}
+
+// FollowerEvent 区块跟随器事件，Block 和 Reorg 二者之一非空
+type FollowerEvent struct {
+	Block *JSONBlock  // 新的规范链区块，按高度递增
+	Reorg *ReorgEvent // 链重组，之后的Block事件从共同祖先的下一个高度重新开始
+}
+
+// Follower 区块跟随器
+// 逐块读取新区块并校验其parentHash与已记录的上一个区块哈希一致；
+// 不一致时回溯到共同祖先，撤销孤块后从共同祖先之后重新读取
+type Follower struct {
+	source BlockSource
+	depth  int
+	next   uint64
+	recent []*JSONBlock // 最近depth个区块，按高度递增
+}
+
+// NewFollower 创建从start高度开始的区块跟随器，depth<=0时使用默认值
+func NewFollower(source BlockSource, start uint64, depth int) *Follower {
+	if depth <= 0 {
+		depth = DefaultFollowerDepth
+	}
+
+	return &Follower{source: source, depth: depth, next: start}
+}
+
+// Next 返回下一个待读取的区块号
+func (f *Follower) Next() uint64 {
+	return f.next
+}
+
+// Poll 读取到当前链头为止的新区块，按发生顺序返回区块和重组事件
+func (f *Follower) Poll(ctx context.Context) ([]FollowerEvent, error) {
+	latest, err := f.source.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []FollowerEvent
+	for f.next <= latest {
+		block, err := f.source.GetJSONBlockByNumber(ctx, f.next, false)
+		if err != nil {
+			return events, err
+		}
+
+		if tip := f.tip(); tip != nil && block.ParentHash != tip.Hash {
+			reorg, err := f.rollback(ctx)
+			if err != nil {
+				return events, err
+			}
+			if reorg == nil {
+				// 已记录的链头仍在规范链上，读到的区块来自尚未同步的节点，下一轮重新读取
+				return events, nil
+			}
+			events = append(events, FollowerEvent{Reorg: reorg})
+			continue
+		}
+
+		f.push(block)
+		events = append(events, FollowerEvent{Block: block})
+	}
+
+	return events, nil
+}
+
+// Run 按interval轮询新区块并依次交给handle处理，直到ctx被取消或handle返回错误
+// 读取区块失败时在下一轮重试
+func (f *Follower) Run(ctx context.Context, interval time.Duration, handle func(context.Context, FollowerEvent) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := f.Poll(ctx)
+		for _, event := range events {
+			if err := handle(ctx, event); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, ErrReorgTooDeep) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// tip 返回已记录的最新区块
+func (f *Follower) tip() *JSONBlock {
+	if len(f.recent) == 0 {
+		return nil
+	}
+	return f.recent[len(f.recent)-1]
+}
+
+// push 记录新的规范链区块，只保留最近depth个
+func (f *Follower) push(block *JSONBlock) {
+	f.recent = append(f.recent, block)
+	if len(f.recent) > f.depth {
+		f.recent = f.recent[len(f.recent)-f.depth:]
+	}
+	f.next = block.Number + 1
+}
+
+// rollback 从最新区块向前逐个与规范链比较，找到共同祖先后撤销其后的区块
+// 找不到共同祖先时不修改已记录的区块；链头仍在规范链上时没有发生重组，返回nil
+func (f *Follower) rollback(ctx context.Context) (*ReorgEvent, error) {
+	reorg := &ReorgEvent{}
+
+	for i := len(f.recent) - 1; i >= 0; i-- {
+		block := f.recent[i]
+
+		canonical, err := f.source.GetJSONBlockByNumber(ctx, block.Number, false)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		if canonical != nil && canonical.Hash == block.Hash {
+			if reorg.Depth == 0 {
+				return nil, nil
+			}
+			reorg.CommonAncestor = block.Number
+			f.recent = f.recent[:i+1]
+			f.next = block.Number + 1
+			return reorg, nil
+		}
+
+		reorg.Depth++
+		reorg.Hashes = append(reorg.Hashes, block.Hash)
+		reorg.Retracted = append(reorg.Retracted, block)
+	}
+
+	return nil, fmt.Errorf("%w: 已回溯 %d 个区块仍未找到共同祖先", ErrReorgTooDeep, reorg.Depth)
+}
@@ -0,0 +1,206 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeChain 可分叉的内存链，区块哈希由分支名和高度生成
+type fakeChain struct {
+	blocks []*JSONBlock
+}
+
+// newFakeChain 创建分支名为branch、包含区块 0..n-1 的链
+func newFakeChain(branch string, n int) *fakeChain {
+	chain := &fakeChain{}
+	chain.extend(branch, n)
+	return chain
+}
+
+// extend 在链尾追加n个属于branch分支的区块
+func (c *fakeChain) extend(branch string, n int) {
+	for i := 0; i < n; i++ {
+		number := uint64(len(c.blocks))
+		block := &JSONBlock{BlockHeader: BlockHeader{Number: number, Hash: fmt.Sprintf("%s-%d", branch, number)}}
+		if number > 0 {
+			block.ParentHash = c.blocks[number-1].Hash
+		}
+		c.blocks = append(c.blocks, block)
+	}
+}
+
+// fork 丢弃ancestor之后的区块，并在其后接上n个属于branch分支的区块
+func (c *fakeChain) fork(ancestor uint64, branch string, n int) {
+	c.blocks = c.blocks[:ancestor+1]
+	c.extend(branch, n)
+}
+
+func (c *fakeChain) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	return uint64(len(c.blocks) - 1), nil
+}
+
+func (c *fakeChain) GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*JSONBlock, error) {
+	if number >= uint64(len(c.blocks)) {
+		return nil, fmt.Errorf("区块 %d: %w", number, ErrNotFound)
+	}
+	return c.blocks[number], nil
+}
+
+// blockNumbers 提取事件中的区块号，重组事件记为 -1
+func blockNumbers(events []FollowerEvent) []int {
+	numbers := make([]int, len(events))
+	for i, event := range events {
+		if event.Reorg != nil {
+			numbers[i] = -1
+		} else {
+			numbers[i] = int(event.Block.Number)
+		}
+	}
+	return numbers
+}
+
+func TestFollowerLinearChain(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain("a", 4)
+	follower := NewFollower(chain, 1, 8)
+
+	events, err := follower.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+	if got := fmt.Sprint(blockNumbers(events)); got != "[1 2 3]" {
+		t.Fatalf("事件 = %s, want [1 2 3]", got)
+	}
+
+	chain.extend("a", 2)
+	events, err = follower.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+	if got := fmt.Sprint(blockNumbers(events)); got != "[4 5]" {
+		t.Fatalf("事件 = %s, want [4 5]", got)
+	}
+}
+
+func TestFollowerReorg(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain("a", 6) // a-0 .. a-5
+	follower := NewFollower(chain, 0, 8)
+	if _, err := follower.Poll(ctx); err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+
+	// 在区块3之后分叉：a-4、a-5 被 b-4、b-5、b-6 取代
+	chain.fork(3, "b", 3)
+	events, err := follower.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+	if got := fmt.Sprint(blockNumbers(events)); got != "[-1 4 5 6]" {
+		t.Fatalf("事件 = %s, want [-1 4 5 6]", got)
+	}
+
+	reorg := events[0].Reorg
+	if reorg.Depth != 2 || reorg.CommonAncestor != 3 {
+		t.Errorf("重组深度/共同祖先 = %d/%d, want 2/3", reorg.Depth, reorg.CommonAncestor)
+	}
+	if got := fmt.Sprint(reorg.Hashes); got != "[a-5 a-4]" {
+		t.Errorf("撤销的区块 = %s, want [a-5 a-4]", got)
+	}
+	for _, event := range events[1:] {
+		if event.Block.Hash != fmt.Sprintf("b-%d", event.Block.Number) {
+			t.Errorf("区块 %d 不在新分支上: %s", event.Block.Number, event.Block.Hash)
+		}
+	}
+}
+
+func TestFollowerReorgReplacingTip(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain("a", 3)
+	follower := NewFollower(chain, 0, 8)
+	if _, err := follower.Poll(ctx); err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+
+	// 同高度替换链头，新区块到来时才能通过parentHash发现
+	chain.fork(1, "b", 1)
+	if events, err := follower.Poll(ctx); err != nil || len(events) != 0 {
+		t.Fatalf("链头未增长时不应产生事件: %v, %v", events, err)
+	}
+
+	chain.extend("b", 1)
+	events, err := follower.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+	if got := fmt.Sprint(blockNumbers(events)); got != "[-1 2 3]" {
+		t.Fatalf("事件 = %s, want [-1 2 3]", got)
+	}
+	if got := fmt.Sprint(events[0].Reorg.Hashes); got != "[a-2]" {
+		t.Errorf("撤销的区块 = %s, want [a-2]", got)
+	}
+}
+
+// staleChain 第一次读取指定高度时返回另一分支的区块，模拟负载均衡后面尚未同步的节点
+type staleChain struct {
+	*fakeChain
+	stale map[uint64]*JSONBlock
+}
+
+func (c *staleChain) GetJSONBlockByNumber(ctx context.Context, number uint64, fullTx bool) (*JSONBlock, error) {
+	if block, ok := c.stale[number]; ok {
+		delete(c.stale, number)
+		return block, nil
+	}
+	return c.fakeChain.GetJSONBlockByNumber(ctx, number, fullTx)
+}
+
+func TestFollowerIgnoresStaleBlock(t *testing.T) {
+	ctx := context.Background()
+	chain := &staleChain{fakeChain: newFakeChain("a", 3), stale: make(map[uint64]*JSONBlock)}
+	follower := NewFollower(chain, 0, 8)
+	if _, err := follower.Poll(ctx); err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+
+	chain.extend("a", 2)
+	chain.stale[3] = &JSONBlock{BlockHeader: BlockHeader{Number: 3, Hash: "b-3", ParentHash: "b-2"}}
+
+	// 链头a-2仍是规范链区块，不应产生深度为0的重组事件
+	events, err := follower.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+	if len(events) != 0 || follower.Next() != 3 {
+		t.Fatalf("事件 = %v, Next = %d, want 无事件且 Next = 3", blockNumbers(events), follower.Next())
+	}
+
+	events, err = follower.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+	if got := fmt.Sprint(blockNumbers(events)); got != "[3 4]" {
+		t.Fatalf("事件 = %s, want [3 4]", got)
+	}
+}
+
+func TestFollowerReorgTooDeep(t *testing.T) {
+	ctx := context.Background()
+	chain := newFakeChain("a", 10)
+	follower := NewFollower(chain, 0, 3)
+	if _, err := follower.Poll(ctx); err != nil {
+		t.Fatalf("Poll失败: %v", err)
+	}
+
+	// 分叉点早于保留的最近3个区块
+	chain.fork(5, "b", 6)
+	_, err := follower.Poll(ctx)
+	if !errors.Is(err, ErrReorgTooDeep) {
+		t.Fatalf("err = %v, want ErrReorgTooDeep", err)
+	}
+	if follower.Next() != 10 {
+		t.Errorf("失败后不应修改进度, Next = %d", follower.Next())
+	}
+}
@@ -0,0 +1,468 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// DefaultLogChunkSize 历史日志查询每次请求的最大区块数，节点提示范围超限时自动减半
+	DefaultLogChunkSize = 10000
+	// logBufferSize 日志通道的缓冲大小
+	logBufferSize = 128
+)
+
+// FilterLogs 查询区块范围内的日志
+// 按 DefaultLogChunkSize 分段请求，节点提示查询范围或结果数量超限时将分段减半后重试；
+// query.BlockHash 非nil时直接查询该区块。范围的含义与 eth_getLogs 一致：
+// FromBlock、ToBlock 为nil时表示最新区块，也可以使用 rpc.LatestBlockNumber、rpc.SafeBlockNumber、
+// rpc.FinalizedBlockNumber 标签，pending 等其他负数返回错误
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if query.BlockHash != nil {
+		return c.filterLogs(ctx, query)
+	}
+
+	from, err := c.resolveLogBlock(ctx, query.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := c.resolveLogBlock(ctx, query.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []types.Log
+	chunk := uint64(DefaultLogChunkSize)
+	for from <= to {
+		end := min(from+chunk-1, to)
+
+		q := query
+		q.FromBlock = new(big.Int).SetUint64(from)
+		q.ToBlock = new(big.Int).SetUint64(end)
+
+		result, err := c.filterLogs(ctx, q)
+		if err != nil {
+			if isRangeLimit(err) && chunk > 1 {
+				chunk = max(chunk/2, 1)
+				continue
+			}
+			return nil, err
+		}
+
+		logs = append(logs, result...)
+		from = end + 1
+	}
+
+	return logs, nil
+}
+
+// resolveLogBlock 将日志查询范围的区块号或标签解析为区块高度，nil表示最新区块
+func (c *Client) resolveLogBlock(ctx context.Context, number *big.Int) (uint64, error) {
+	if number == nil {
+		return c.GetLatestBlockNumber(ctx)
+	}
+	if number.IsUint64() {
+		return number.Uint64(), nil
+	}
+	if !number.IsInt64() {
+		return 0, fmt.Errorf("无效的区块号 %s", number)
+	}
+
+	switch tag := rpc.BlockNumber(number.Int64()); tag {
+	case rpc.LatestBlockNumber:
+		return c.GetLatestBlockNumber(ctx)
+	case rpc.SafeBlockNumber, rpc.FinalizedBlockNumber:
+		ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+		defer cancel()
+
+		header, err := c.Client.HeaderByNumber(ctx, number)
+		if err != nil {
+			return 0, wrapError(err, "获取 %s 区块号失败", tag)
+		}
+		return header.Number.Uint64(), nil
+	case rpc.PendingBlockNumber:
+		return 0, errors.New("不支持查询 pending 区块的日志")
+	default:
+		return 0, fmt.Errorf("无效的区块号 %s", number)
+	}
+}
+
+// filterLogs 单次 eth_getLogs 请求
+func (c *Client) filterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	logs, err := c.Client.FilterLogs(ctx, query)
+	if err != nil {
+		if query.BlockHash != nil {
+			return nil, wrapError(err, "查询区块 %s 的日志失败", query.BlockHash.Hex())
+		}
+		return nil, wrapError(err, "查询区块 %s-%s 的日志失败", query.FromBlock, query.ToBlock)
+	}
+
+	return logs, nil
+}
+
+// SubscribeFilterLogs 订阅新产生的日志
+// 节点支持订阅时使用 eth_subscribe logs，订阅断开后改为跟随 SubscribeNewHeads 逐块查询，
+// 不支持订阅时直接逐块查询。query.FromBlock 非nil时先补齐从该区块开始的历史日志。
+// 链重组时订阅推送的日志 Removed 为true，逐块查询则会重新推送新分支上的日志。
+// ctx 取消后通道关闭。
+func (c *Client) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery) (<-chan types.Log, error) {
+	head, err := c.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &logStream{
+		client:    c,
+		query:     query,
+		logs:      make(chan types.Log, logBufferSize),
+		nextBlock: head + 1,
+	}
+	if query.FromBlock != nil {
+		if s.nextBlock, err = c.resolveLogBlock(ctx, query.FromBlock); err != nil {
+			return nil, err
+		}
+	}
+	s.query.FromBlock, s.query.ToBlock = nil, nil
+
+	var (
+		sub ethereum.Subscription
+		raw chan types.Log
+	)
+	if c.supportsSubscriptions() {
+		raw = make(chan types.Log, logBufferSize)
+		sub, err = c.Client.SubscribeFilterLogs(ctx, s.query, raw)
+		if err != nil {
+			return nil, wrapError(err, "订阅日志失败")
+		}
+	}
+
+	go s.run(ctx, sub, raw)
+	return s.logs, nil
+}
+
+// logStream 一次SubscribeFilterLogs调用的推送状态
+// nextBlock/nextIndex 标记下一条待推送日志的位置，用于补齐历史日志和订阅切换时去重
+type logStream struct {
+	client    *Client
+	query     ethereum.FilterQuery
+	logs      chan types.Log
+	nextBlock uint64
+	nextIndex uint
+}
+
+// run 推送日志直到ctx取消
+func (s *logStream) run(ctx context.Context, sub ethereum.Subscription, raw chan types.Log) {
+	defer close(s.logs)
+
+	if sub != nil {
+		if !s.forward(ctx, sub, raw) {
+			return
+		}
+	}
+	s.follow(ctx)
+}
+
+// forward 补齐历史日志后转发订阅推送的日志，订阅断开时返回true
+func (s *logStream) forward(ctx context.Context, sub ethereum.Subscription, raw chan types.Log) bool {
+	defer sub.Unsubscribe()
+
+	// 订阅已先建立，补齐期间产生的日志缓存在raw中，转发时按位置去重
+	if err := s.backfill(ctx, nil); err != nil {
+		return ctx.Err() == nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-sub.Err():
+			return true
+		case log := <-raw:
+			if !s.emit(ctx, log) {
+				return false
+			}
+		}
+	}
+}
+
+// follow 跟随新区块头逐块查询日志，区块号出现跳跃时先按范围补齐
+func (s *logStream) follow(ctx context.Context) {
+	heads, err := s.client.SubscribeNewHeads(ctx)
+	for err != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(MinResubscribeBackoff):
+		}
+		heads, err = s.client.SubscribeNewHeads(ctx)
+	}
+
+	first := true
+	for head := range heads {
+		if head.Number < s.nextBlock {
+			if first {
+				// 订阅从当前链头开始，早于待推送位置的区块已处理过
+				first = false
+				continue
+			}
+			// 重组：从新分支的该高度重新推送
+			s.nextBlock, s.nextIndex = head.Number, 0
+		}
+		first = false
+
+		for {
+			err := s.backfill(ctx, new(big.Int).Sub(new(big.Int).SetUint64(head.Number), big.NewInt(1)))
+			if err == nil {
+				hash := common.HexToHash(head.Hash)
+				q := s.query
+				q.BlockHash = &hash
+				var logs []types.Log
+				if logs, err = s.client.FilterLogs(ctx, q); err == nil {
+					err = s.emitAll(ctx, logs)
+				}
+			}
+			if err == nil || ctx.Err() != nil {
+				break
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(MinResubscribeBackoff):
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		s.nextBlock, s.nextIndex = head.Number+1, 0
+	}
+}
+
+// backfill 按范围查询并推送 nextBlock 到 to（nil表示最新区块）之间尚未推送的日志
+func (s *logStream) backfill(ctx context.Context, to *big.Int) error {
+	if to != nil && (to.Sign() < 0 || to.Uint64() < s.nextBlock) {
+		return nil
+	}
+
+	q := s.query
+	q.FromBlock = new(big.Int).SetUint64(s.nextBlock)
+	q.ToBlock = to
+	logs, err := s.client.FilterLogs(ctx, q)
+	if err != nil {
+		return err
+	}
+	return s.emitAll(ctx, logs)
+}
+
+// emitAll 依次推送日志
+func (s *logStream) emitAll(ctx context.Context, logs []types.Log) error {
+	for _, log := range logs {
+		if !s.emit(ctx, log) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// emit 推送尚未推送过的日志并推进位置，ctx取消时返回false
+// 被重组移除的日志总是推送，并将位置回退到该日志，新分支上相同或更早位置的日志会再次推送
+func (s *logStream) emit(ctx context.Context, log types.Log) bool {
+	if log.Removed {
+		if !s.send(ctx, log) {
+			return false
+		}
+		if s.seen(log) {
+			s.nextBlock, s.nextIndex = log.BlockNumber, log.Index
+		}
+		return true
+	}
+
+	if s.seen(log) {
+		return true
+	}
+	if !s.send(ctx, log) {
+		return false
+	}
+	s.nextBlock, s.nextIndex = log.BlockNumber, log.Index+1
+	return true
+}
+
+// seen 日志位置是否早于下一条待推送日志的位置
+func (s *logStream) seen(log types.Log) bool {
+	return log.BlockNumber < s.nextBlock || (log.BlockNumber == s.nextBlock && log.Index < s.nextIndex)
+}
+
+// send 推送日志，ctx取消时返回false
+func (s *logStream) send(ctx context.Context, log types.Log) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case s.logs <- log:
+		return true
+	}
+}
+
+// Event 合约事件，按ABI解码后Name和Args非空
+type Event struct {
+	Name        string                 `json:"name,omitempty"`
+	Address     string                 `json:"address"`
+	BlockNumber uint64                 `json:"blockNumber"`
+	BlockHash   string                 `json:"blockHash"`
+	TxHash      string                 `json:"transactionHash"`
+	LogIndex    uint                   `json:"logIndex"`
+	Removed     bool                   `json:"removed"`
+	Topics      []string               `json:"topics"`
+	Data        string                 `json:"data"`
+	Args        map[string]interface{} `json:"args,omitempty"`
+}
+
+// NewEvent 将日志转换为未解码的事件
+func NewEvent(log types.Log) *Event {
+	topics := make([]string, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = topic.Hex()
+	}
+
+	return &Event{
+		Address:     log.Address.Hex(),
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash.Hex(),
+		TxHash:      log.TxHash.Hex(),
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+		Topics:      topics,
+		Data:        hexutil.Encode(log.Data),
+	}
+}
+
+// DecodeLog 按合约ABI将日志解码为事件，indexed参数从topics解析，其余参数从data解析
+func DecodeLog(contractABI abi.ABI, log types.Log) (*Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, errors.New("日志没有topic，无法识别事件（匿名事件）")
+	}
+
+	event, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("未知的事件 %s: %w", log.Topics[0].Hex(), err)
+	}
+
+	args := make(map[string]interface{})
+	if err := event.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+		return nil, fmt.Errorf("解码事件 %s 的参数失败: %w", event.Name, err)
+	}
+
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("解码事件 %s 的indexed参数失败: %w", event.Name, err)
+	}
+
+	decoded := NewEvent(log)
+	decoded.Name = event.Name
+	decoded.Args = args
+	return decoded, nil
+}
+
+// Logs 单个合约的事件查询与订阅，日志按合约ABI解码
+type Logs struct {
+	client  *Client
+	address common.Address
+	abi     abi.ABI
+}
+
+// NewLogs 创建合约事件查询器
+func NewLogs(client *Client, address common.Address, contractABI abi.ABI) *Logs {
+	return &Logs{client: client, address: address, abi: contractABI}
+}
+
+// Query 查询 [from, to] 区块范围内的事件，to为nil时查询到最新区块
+// events 为空时返回合约的全部事件，否则只返回指定名称的事件；无法按ABI解码的日志以未解码的事件返回
+func (l *Logs) Query(ctx context.Context, from uint64, to *big.Int, events ...string) ([]*Event, error) {
+	query, err := l.filterQuery(events)
+	if err != nil {
+		return nil, err
+	}
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = to
+
+	logs, err := l.client.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := make([]*Event, 0, len(logs))
+	for _, log := range logs {
+		decoded = append(decoded, l.decode(log))
+	}
+	return decoded, nil
+}
+
+// Subscribe 订阅新产生的事件，events含义同Query
+// 无法解码的日志以未解码的事件推送；ctx 取消后通道关闭
+func (l *Logs) Subscribe(ctx context.Context, events ...string) (<-chan *Event, error) {
+	query, err := l.filterQuery(events)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := l.client.SubscribeFilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := make(chan *Event, logBufferSize)
+	go func() {
+		defer close(decoded)
+		for log := range logs {
+			select {
+			case <-ctx.Done():
+				return
+			case decoded <- l.decode(log):
+			}
+		}
+	}()
+
+	return decoded, nil
+}
+
+// decode 按合约ABI解码日志，无法解码时（例如ABI与链上合约不一致）返回未解码的事件
+func (l *Logs) decode(log types.Log) *Event {
+	if event, err := DecodeLog(l.abi, log); err == nil {
+		return event
+	}
+	return NewEvent(log)
+}
+
+// filterQuery 构造按合约地址和事件名称过滤的查询
+func (l *Logs) filterQuery(events []string) (ethereum.FilterQuery, error) {
+	query := ethereum.FilterQuery{Addresses: []common.Address{l.address}}
+	if len(events) == 0 {
+		return query, nil
+	}
+
+	ids := make([]common.Hash, 0, len(events))
+	for _, name := range events {
+		event, ok := l.abi.Events[name]
+		if !ok {
+			return query, fmt.Errorf("合约ABI中不存在事件 %s", name)
+		}
+		ids = append(ids, event.ID)
+	}
+	query.Topics = [][]common.Hash{ids}
+	return query, nil
+}
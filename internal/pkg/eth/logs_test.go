@@ -0,0 +1,245 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// logBackend 限制 eth_getLogs 查询范围并可以主动断开日志订阅的后端
+type logBackend struct {
+	Backend
+	maxRange uint64 // 超过该区块数的范围查询返回超限错误，0表示不限制
+	subs     chan *droppableSubscription
+
+	mu     sync.Mutex
+	ranges []string // 每次范围查询的区块范围
+}
+
+func (b *logBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if query.BlockHash == nil && query.FromBlock != nil && query.ToBlock != nil {
+		from, to := query.FromBlock.Uint64(), query.ToBlock.Uint64()
+		b.mu.Lock()
+		b.ranges = append(b.ranges, fmt.Sprintf("%d-%d", from, to))
+		b.mu.Unlock()
+		if b.maxRange > 0 && to-from+1 > b.maxRange {
+			return nil, errors.New("query returned more than 10000 results")
+		}
+	}
+	return b.Backend.FilterLogs(ctx, query)
+}
+
+func (b *logBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	sub, err := b.Backend.SubscribeFilterLogs(ctx, query, ch)
+	if err != nil {
+		return nil, err
+	}
+	dropped := &droppableSubscription{Subscription: sub, errc: make(chan error, 1)}
+	b.subs <- dropped
+	return dropped, nil
+}
+
+// newLogTestCounter 部署计数器合约并将客户端的后端替换为logBackend
+func newLogTestCounter(t *testing.T) (*Client, *logBackend, *CounterService, func()) {
+	t.Helper()
+
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{BlockTime: "10ms"})
+	counter := deployCounter(t, client, backend, signer)
+	logs := &logBackend{Backend: client.Client, subs: make(chan *droppableSubscription, 4)}
+	client.Client = logs
+	return client, logs, counter, func() { backend.Commit() }
+}
+
+// increment 发送increment交易并出块
+func increment(t *testing.T, counter *CounterService, commit func(), amount int64) {
+	t.Helper()
+	if _, err := counter.Increment(context.Background(), big.NewInt(amount), callGas); err != nil {
+		t.Fatalf("Increment失败: %v", err)
+	}
+	commit()
+}
+
+func TestFilterLogsHalvesChunk(t *testing.T) {
+	client, backend, counter, commit := newLogTestCounter(t)
+	for i := int64(1); i <= 3; i++ {
+		increment(t, counter, commit, i)
+	}
+	backend.maxRange = 2
+
+	logs, err := client.FilterLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{counter.Address()},
+	})
+	if err != nil {
+		t.Fatalf("FilterLogs失败: %v", err)
+	}
+	if len(logs) != 3 {
+		t.Fatalf("日志数 = %d, want 3", len(logs))
+	}
+	for i := 1; i < len(logs); i++ {
+		if logs[i].BlockNumber <= logs[i-1].BlockNumber {
+			t.Errorf("日志未按区块顺序返回: %d 在 %d 之后", logs[i].BlockNumber, logs[i-1].BlockNumber)
+		}
+	}
+
+	// 分段依次减半直到不超过2个区块，之后以该大小查询完整个范围
+	ranges := strings.Join(backend.ranges, " ")
+	if !strings.HasPrefix(ranges, "0-4 ") || !strings.HasSuffix(ranges, "0-3 0-1 2-3 4-4") {
+		t.Errorf("查询范围 = %s, want 以 0-4 开始、以 0-3 0-1 2-3 4-4 结束", ranges)
+	}
+}
+
+func TestLogStreamDedupByPosition(t *testing.T) {
+	s := &logStream{logs: make(chan types.Log, 8), nextBlock: 5, nextIndex: 2}
+	positions := [][2]uint64{{4, 9}, {5, 1}, {5, 2}, {5, 2}, {5, 3}, {6, 0}, {5, 4}}
+	for _, p := range positions {
+		s.emit(context.Background(), types.Log{BlockNumber: p[0], Index: uint(p[1])})
+	}
+	close(s.logs)
+
+	var got []string
+	for log := range s.logs {
+		got = append(got, fmt.Sprintf("%d/%d", log.BlockNumber, log.Index))
+	}
+	if strings.Join(got, " ") != "5/2 5/3 6/0" {
+		t.Errorf("推送的日志位置 = %v, want [5/2 5/3 6/0]", got)
+	}
+	if s.nextBlock != 6 || s.nextIndex != 1 {
+		t.Errorf("下一个位置 = %d/%d, want 6/1", s.nextBlock, s.nextIndex)
+	}
+}
+
+func TestLogStreamRewindsOnRemoved(t *testing.T) {
+	s := &logStream{logs: make(chan types.Log, 8), nextBlock: 5}
+	logs := []types.Log{
+		{BlockNumber: 5, Index: 0},
+		{BlockNumber: 6, Index: 0},
+		// 重组移除两条日志，位置回退到较早的 5/0
+		{BlockNumber: 6, Index: 0, Removed: true},
+		{BlockNumber: 5, Index: 0, Removed: true},
+		// 新分支上相同位置的日志再次推送
+		{BlockNumber: 5, Index: 0},
+		{BlockNumber: 6, Index: 1},
+	}
+	for _, log := range logs {
+		s.emit(context.Background(), log)
+	}
+	close(s.logs)
+
+	var got []string
+	for log := range s.logs {
+		got = append(got, fmt.Sprintf("%d/%d/%v", log.BlockNumber, log.Index, log.Removed))
+	}
+	if want := "5/0/false 6/0/false 6/0/true 5/0/true 5/0/false 6/1/false"; strings.Join(got, " ") != want {
+		t.Errorf("推送的日志 = %v, want %s", got, want)
+	}
+	if s.nextBlock != 6 || s.nextIndex != 2 {
+		t.Errorf("下一个位置 = %d/%d, want 6/2", s.nextBlock, s.nextIndex)
+	}
+}
+
+func TestFilterLogsBlockTags(t *testing.T) {
+	client, _, counter, commit := newLogTestCounter(t)
+	for i := int64(1); i <= 2; i++ {
+		increment(t, counter, commit, i)
+	}
+	ctx := context.Background()
+	address := []common.Address{counter.Address()}
+
+	// FromBlock 为nil时与 eth_getLogs 一致，只查询最新区块
+	for _, from := range []*big.Int{nil, big.NewInt(int64(rpc.LatestBlockNumber))} {
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: from, Addresses: address})
+		if err != nil {
+			t.Fatalf("FilterLogs(%v)失败: %v", from, err)
+		}
+		if len(logs) != 1 {
+			t.Errorf("FilterLogs(%v) 日志数 = %d, want 1", from, len(logs))
+		}
+	}
+
+	for _, from := range []*big.Int{big.NewInt(int64(rpc.PendingBlockNumber)), big.NewInt(-5)} {
+		if _, err := client.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: from, Addresses: address}); err == nil {
+			t.Errorf("FilterLogs(%v) 应返回错误", from)
+		}
+	}
+}
+
+// nextEvent 在超时前读取下一个事件
+func nextEvent(t *testing.T, events <-chan *Event) *Event {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("事件通道已关闭")
+		}
+		return event
+	case <-time.After(10 * time.Second):
+		t.Fatal("等待事件超时")
+		return nil
+	}
+}
+
+func TestSubscribeFallsBackToPolling(t *testing.T) {
+	client, backend, counter, commit := newLogTestCounter(t)
+	contractABI, err := LoadABI(counterABIPath)
+	if err != nil {
+		t.Fatalf("加载ABI失败: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := NewLogs(client, counter.Address(), contractABI).Subscribe(ctx, "CountIncremented")
+	if err != nil {
+		t.Fatalf("订阅事件失败: %v", err)
+	}
+	sub := <-backend.subs
+
+	increment(t, counter, commit, 1)
+	if event := nextEvent(t, events); event.Name != "CountIncremented" || event.Args["newCount"].(*big.Int).Int64() != 1 {
+		t.Fatalf("订阅推送的事件 = %+v", event)
+	}
+
+	// 订阅断开后改为逐块查询，已推送过的日志不再重复推送
+	sub.drop()
+	increment(t, counter, commit, 2)
+	if event := nextEvent(t, events); event.Args["newCount"].(*big.Int).Int64() != 3 {
+		t.Fatalf("断开后推送的事件 newCount = %v, want 3", event.Args["newCount"])
+	}
+	increment(t, counter, commit, 3)
+	if event := nextEvent(t, events); event.Args["newCount"].(*big.Int).Int64() != 6 {
+		t.Fatalf("逐块查询推送的事件 newCount = %v, want 6", event.Args["newCount"])
+	}
+}
+
+func TestLogsQueryReturnsUndecodedEvents(t *testing.T) {
+	client, _, counter, commit := newLogTestCounter(t)
+	increment(t, counter, commit, 1)
+
+	// ABI中没有CountIncremented，日志无法解码
+	emptyABI, err := abi.JSON(strings.NewReader("[]"))
+	if err != nil {
+		t.Fatalf("解析ABI失败: %v", err)
+	}
+	events, err := NewLogs(client, counter.Address(), emptyABI).Query(context.Background(), 0, nil)
+	if err != nil {
+		t.Fatalf("Query失败: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("事件数 = %d, want 1", len(events))
+	}
+	if events[0].Name != "" || events[0].Args != nil || len(events[0].Topics) != 2 {
+		t.Errorf("应返回未解码的事件, got %+v", events[0])
+	}
+}
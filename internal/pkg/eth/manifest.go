@@ -0,0 +1,103 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Deployment 一次合约部署的记录
+type Deployment struct {
+	Address      string `json:"address"`
+	TxHash       string `json:"transactionHash,omitempty"` // 地址上已有代码、本次未部署时为空
+	BlockNumber  uint64 `json:"blockNumber,omitempty"`
+	BytecodeHash string `json:"bytecodeHash"` // 合约创建代码（含构造函数参数）的keccak256
+	Salt         string `json:"salt"`
+	Deployer     string `json:"deployer"` // CREATE2部署工厂地址
+}
+
+// Manifest 本地部署清单，按网络和合约名称记录部署结果，以JSON文件保存：
+//
+//	{"sepolia": {"SimpleCounter": {"address": "0x...", "transactionHash": "0x...", ...}}}
+type Manifest struct {
+	path string
+
+	mu       sync.Mutex
+	networks map[string]map[string]*Deployment
+}
+
+// LoadManifest 读取部署清单，文件不存在时返回空清单，首次记录时创建
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{path: path, networks: make(map[string]map[string]*Deployment)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取部署清单失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &m.networks); err != nil {
+		return nil, fmt.Errorf("解析部署清单 %s 失败: %w", path, err)
+	}
+	if m.networks == nil {
+		m.networks = make(map[string]map[string]*Deployment)
+	}
+	return m, nil
+}
+
+// Get 返回合约在网络上的部署记录
+func (m *Manifest) Get(network, name string) (*Deployment, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deployment, ok := m.networks[network][name]
+	if !ok {
+		return nil, false
+	}
+	copied := *deployment
+	return &copied, true
+}
+
+// Record 记录合约在网络上的部署结果（覆盖同名记录）并写回文件
+func (m *Manifest) Record(network, name string, deployment *Deployment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.networks[network] == nil {
+		m.networks[network] = make(map[string]*Deployment)
+	}
+	copied := *deployment
+	m.networks[network][name] = &copied
+
+	return m.save()
+}
+
+// save 先写临时文件再重命名，避免写入中断时损坏已有清单
+func (m *Manifest) save() error {
+	data, err := json.MarshalIndent(m.networks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码部署清单失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("写入部署清单失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入部署清单失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入部署清单失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return fmt.Errorf("写入部署清单失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package eth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+
+	// 文件不存在时返回空清单
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("加载部署清单失败: %v", err)
+	}
+	if _, ok := manifest.Get("sepolia", "SimpleCounter"); ok {
+		t.Fatal("空清单不应有记录")
+	}
+
+	sepolia := &Deployment{
+		Address:      "0x1111111111111111111111111111111111111111",
+		TxHash:       "0xaaaa",
+		BlockNumber:  42,
+		BytecodeHash: "0xbbbb",
+		Salt:         "0xcccc",
+		Deployer:     DefaultCreate2Deployer.Hex(),
+	}
+	holesky := &Deployment{Address: "0x2222222222222222222222222222222222222222", BytecodeHash: "0xbbbb", Salt: "0xcccc", Deployer: DefaultCreate2Deployer.Hex()}
+	if err := manifest.Record("sepolia", "SimpleCounter", sepolia); err != nil {
+		t.Fatalf("记录部署失败: %v", err)
+	}
+	if err := manifest.Record("holesky", "SimpleCounter", holesky); err != nil {
+		t.Fatalf("记录部署失败: %v", err)
+	}
+
+	// 记录和返回的都是副本
+	sepolia.Address = "0x0"
+	got, _ := manifest.Get("sepolia", "SimpleCounter")
+	got.TxHash = "0x0"
+	if again, _ := manifest.Get("sepolia", "SimpleCounter"); again.Address == "0x0" || again.TxHash == "0x0" {
+		t.Errorf("修改副本影响了清单: %+v", again)
+	}
+
+	loaded, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("重新加载部署清单失败: %v", err)
+	}
+	for network, want := range map[string]Deployment{
+		"sepolia": {Address: "0x1111111111111111111111111111111111111111", TxHash: "0xaaaa", BlockNumber: 42,
+			BytecodeHash: "0xbbbb", Salt: "0xcccc", Deployer: DefaultCreate2Deployer.Hex()},
+		"holesky": *holesky,
+	} {
+		got, ok := loaded.Get(network, "SimpleCounter")
+		if !ok || *got != want {
+			t.Errorf("%s 的记录 = %+v, want %+v", network, got, want)
+		}
+	}
+
+	// 写入后不残留临时文件
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("目录中的文件 = %v (%v), want 只有清单文件", entries, err)
+	}
+}
+
+func TestLoadManifestInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+	if _, err := LoadManifest(path); err == nil {
+		t.Error("清单格式错误时应返回错误")
+	}
+}
@@ -0,0 +1,140 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultNonceIdleTimeout 账户空闲超过该时间后，下一次分配nonce前从节点重新同步
+const DefaultNonceIdleTimeout = 30 * time.Second
+
+// NonceSource 从节点读取账户的待处理nonce
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// nonceKey nonce管理的键：链ID + 账户地址
+type nonceKey struct {
+	chainID string
+	address common.Address
+}
+
+// accountNonce 单个账户的本地nonce状态
+type accountNonce struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64   // 下一个从未分配过的nonce
+	gaps     []uint64 // 已分配但发送失败、可复用的nonce（升序）
+	lastUsed time.Time
+}
+
+// NonceManager 本地nonce管理器
+// 同一账户并发发送交易时原子地分配nonce，发送失败的nonce会被优先复用以避免空洞
+type NonceManager struct {
+	source      NonceSource
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	accounts map[nonceKey]*accountNonce
+}
+
+// NewNonceManager 创建nonce管理器，idleTimeout<=0时使用默认值
+func NewNonceManager(source NonceSource, idleTimeout time.Duration) *NonceManager {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultNonceIdleTimeout
+	}
+
+	return &NonceManager{
+		source:      source,
+		idleTimeout: idleTimeout,
+		accounts:    make(map[nonceKey]*accountNonce),
+	}
+}
+
+// account 获取（必要时创建）账户的nonce状态
+func (m *NonceManager) account(chainID *big.Int, address common.Address) *accountNonce {
+	key := nonceKey{chainID: chainID.String(), address: address}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	acc, ok := m.accounts[key]
+	if !ok {
+		acc = &accountNonce{}
+		m.accounts[key] = acc
+	}
+	return acc
+}
+
+// Acquire 为账户分配下一个nonce，优先复用发送失败留下的空洞
+// 首次使用或空闲超时后会先从节点同步待处理nonce
+func (m *NonceManager) Acquire(ctx context.Context, chainID *big.Int, address common.Address) (uint64, error) {
+	acc := m.account(chainID, address)
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	if !acc.synced || time.Since(acc.lastUsed) > m.idleTimeout {
+		nonce, err := m.source.PendingNonceAt(ctx, address)
+		if err != nil {
+			return 0, wrapError(err, "同步账户 %s 的nonce失败", address.Hex())
+		}
+		acc.synced = true
+		acc.next = nonce
+		acc.gaps = nil
+	}
+	acc.lastUsed = time.Now()
+
+	if len(acc.gaps) > 0 {
+		nonce := acc.gaps[0]
+		acc.gaps = acc.gaps[1:]
+		return nonce, nil
+	}
+
+	nonce := acc.next
+	acc.next++
+	return nonce, nil
+}
+
+// Release 归还未成功发送的nonce，供后续交易复用
+func (m *NonceManager) Release(chainID *big.Int, address common.Address, nonce uint64) {
+	acc := m.account(chainID, address)
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	// 期间已重新同步过，归还的nonce已无意义
+	if !acc.synced || nonce >= acc.next {
+		return
+	}
+
+	i := sort.Search(len(acc.gaps), func(i int) bool { return acc.gaps[i] >= nonce })
+	if i < len(acc.gaps) && acc.gaps[i] == nonce {
+		return
+	}
+	acc.gaps = append(acc.gaps, 0)
+	copy(acc.gaps[i+1:], acc.gaps[i:])
+	acc.gaps[i] = nonce
+
+	// 归还的是末尾的nonce时直接回退，保持空洞列表最短
+	for len(acc.gaps) > 0 && acc.gaps[len(acc.gaps)-1] == acc.next-1 {
+		acc.gaps = acc.gaps[:len(acc.gaps)-1]
+		acc.next--
+	}
+}
+
+// Reset 丢弃账户的本地nonce状态，下一次分配时从节点重新同步
+func (m *NonceManager) Reset(chainID *big.Int, address common.Address) {
+	acc := m.account(chainID, address)
+
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+
+	acc.synced = false
+	acc.gaps = nil
+}
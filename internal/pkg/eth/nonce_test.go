@@ -0,0 +1,235 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// fakeNonceSource 返回固定待处理nonce并记录同步次数
+type fakeNonceSource struct {
+	mu    sync.Mutex
+	nonce uint64
+	calls int
+}
+
+func (s *fakeNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return s.nonce, nil
+}
+
+func TestNonceManagerReusesGaps(t *testing.T) {
+	ctx := context.Background()
+	chainID := big.NewInt(1337)
+	address := common.HexToAddress("0x01")
+	source := &fakeNonceSource{nonce: 5}
+	manager := NewNonceManager(source, 0)
+
+	acquire := func() uint64 {
+		t.Helper()
+		nonce, err := manager.Acquire(ctx, chainID, address)
+		if err != nil {
+			t.Fatalf("分配nonce失败: %v", err)
+		}
+		return nonce
+	}
+
+	for want := uint64(5); want < 8; want++ {
+		if got := acquire(); got != want {
+			t.Fatalf("nonce = %d, want %d", got, want)
+		}
+	}
+
+	// 中间的nonce发送失败后应被优先复用
+	manager.Release(chainID, address, 6)
+	if got := acquire(); got != 6 {
+		t.Fatalf("复用空洞 nonce = %d, want 6", got)
+	}
+	if got := acquire(); got != 8 {
+		t.Fatalf("nonce = %d, want 8", got)
+	}
+
+	// 末尾的nonce归还后直接回退
+	manager.Release(chainID, address, 8)
+	manager.Release(chainID, address, 7)
+	if got := acquire(); got != 7 {
+		t.Fatalf("回退后 nonce = %d, want 7", got)
+	}
+
+	if source.calls != 1 {
+		t.Fatalf("同步次数 = %d, want 1", source.calls)
+	}
+
+	// Reset后重新从节点同步
+	source.nonce = 20
+	manager.Reset(chainID, address)
+	if got := acquire(); got != 20 {
+		t.Fatalf("重新同步后 nonce = %d, want 20", got)
+	}
+
+	// 不同链上的同一地址互不影响
+	other, err := manager.Acquire(ctx, big.NewInt(1), address)
+	if err != nil {
+		t.Fatalf("分配nonce失败: %v", err)
+	}
+	if other != 20 {
+		t.Fatalf("其他链 nonce = %d, want 20", other)
+	}
+}
+
+func TestSendConcurrentNonces(t *testing.T) {
+	const senders = 32
+
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		hashes []string
+		errs   []error
+	)
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1)})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			hashes = append(hashes, tx.Hash)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		t.Errorf("并发发送失败: %v", err)
+	}
+	backend.Commit()
+
+	seen := make(map[uint64]bool)
+	for _, hash := range hashes {
+		receipt, err := client.GetTransactionReceipt(ctx, hash)
+		if err != nil {
+			t.Fatalf("获取收据失败: %v", err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("交易 %s 执行失败", hash)
+		}
+
+		tx, _, err := client.GetTransactionByHash(ctx, hash)
+		if err != nil {
+			t.Fatalf("获取交易失败: %v", err)
+		}
+		if seen[tx.Nonce()] {
+			t.Fatalf("nonce %d 被重复使用", tx.Nonce())
+		}
+		seen[tx.Nonce()] = true
+	}
+
+	nonce, err := client.GetNonce(ctx, signer.Address().Hex())
+	if err != nil {
+		t.Fatalf("获取nonce失败: %v", err)
+	}
+	if nonce != senders {
+		t.Fatalf("账户nonce = %d, want %d", nonce, senders)
+	}
+}
+
+func TestSendResyncsOnNonceTooLow(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+
+	if _, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1)}); err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	backend.Commit()
+
+	// 绕过NonceManager用下一个nonce发送交易，使本地状态落后于链上
+	next := uint64(1)
+	if _, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1), Nonce: &next}); err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	backend.Commit()
+
+	tx, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("nonce过低后应重新同步并成功发送: %v", err)
+	}
+	if *tx.Nonce != 2 {
+		t.Fatalf("nonce = %d, want 2", *tx.Nonce)
+	}
+}
+
+// flakySendBackend 发送交易时返回err的后端，forward为true时先将交易转发给节点，模拟节点已接收但响应丢失
+type flakySendBackend struct {
+	Backend
+	forward bool
+	err     error
+}
+
+func (b *flakySendBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.err == nil || b.forward {
+		if err := b.Backend.SendTransaction(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return b.err
+}
+
+func TestSendNonceAfterFailedSend(t *testing.T) {
+	tests := []struct {
+		name    string
+		forward bool
+		err     error
+		want    uint64
+	}{
+		{"节点已接收后连接中断", true, syscall.ECONNRESET, 1},
+		{"节点已接收后超时", true, context.DeadlineExceeded, 1},
+		{"节点已接收后返回503", true, rpc.HTTPError{StatusCode: http.StatusServiceUnavailable}, 1},
+		{"节点明确拒绝", false, &testRPCError{code: -32000, message: "invalid sender"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+			flaky := &flakySendBackend{Backend: client.Client, forward: tt.forward, err: tt.err}
+			client.Client = flaky
+			to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+
+			if _, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1)}); err == nil {
+				t.Fatal("发送失败时应返回错误")
+			}
+
+			// 交易可能已被接收时从节点重新同步，明确拒绝时复用归还的nonce
+			flaky.err = nil
+			tx, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1)})
+			if err != nil {
+				t.Fatalf("再次发送失败: %v", err)
+			}
+			if *tx.Nonce != tt.want {
+				t.Fatalf("nonce = %d, want %d", *tx.Nonce, tt.want)
+			}
+
+			backend.Commit()
+			if receipt, err := client.GetTransactionReceipt(ctx, tx.Hash); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+				t.Errorf("交易未成功上链: %v", err)
+			}
+		})
+	}
+}
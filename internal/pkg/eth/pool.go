@@ -0,0 +1,608 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// DefaultHealthCheckInterval 未配置出块间隔时节点健康检查的间隔
+	DefaultHealthCheckInterval = 12 * time.Second
+	// DefaultMaxHeadLag 节点最新区块落后于所有节点中最高区块的最大允许值，超过则视为不健康
+	DefaultMaxHeadLag = 3
+	// maxErrorRate 错误率（指数移动平均）达到该值的节点视为不健康
+	maxErrorRate = 0.5
+	// ewmaWeight 延迟和错误率指数移动平均中最新样本的权重
+	ewmaWeight = 0.3
+)
+
+// 不能换节点重试的方法
+// 过滤器ID只在创建它的节点上有效；发送交易由 sendRawTransaction 单独去重处理
+var nonRetryableMethods = map[string]bool{
+	"eth_sendTransaction":             true,
+	"eth_newFilter":                   true,
+	"eth_newBlockFilter":              true,
+	"eth_newPendingTransactionFilter": true,
+	"eth_getFilterChanges":            true,
+	"eth_getFilterLogs":               true,
+	"eth_uninstallFilter":             true,
+}
+
+// headSensitiveMethods 结果取决于节点已同步到的区块高度的查询
+// 节点之间高度不同，领先节点上已有的区块或交易在落后节点上返回null
+var headSensitiveMethods = map[string]bool{
+	"eth_getBlockByNumber":                    true,
+	"eth_getBlockByHash":                      true,
+	"eth_getBlockReceipts":                    true,
+	"eth_getTransactionByHash":                true,
+	"eth_getTransactionReceipt":               true,
+	"eth_getTransactionByBlockNumberAndIndex": true,
+	"eth_getTransactionByBlockHashAndIndex":   true,
+	"eth_getBlockTransactionCountByNumber":    true,
+	"eth_getBlockTransactionCountByHash":      true,
+}
+
+// EndpointStatus 单个RPC节点的健康状态
+type EndpointStatus struct {
+	URL       string        `json:"url"`
+	Latency   time.Duration `json:"latency"`
+	ErrorRate float64       `json:"errorRate"`
+	Head      uint64        `json:"head"`
+	HeadLag   uint64        `json:"headLag"`
+	Healthy   bool          `json:"healthy"`
+}
+
+// endpoint 单个RPC节点及其统计数据，字段由EndpointPool.mu保护
+type endpoint struct {
+	url       *url.URL
+	latency   time.Duration // 成功请求耗时的指数移动平均
+	errorRate float64       // 请求失败率的指数移动平均
+	head      uint64        // 健康检查得到的最新区块号
+}
+
+// EndpointPool 多个HTTP RPC节点的负载均衡与故障转移，以 http.RoundTripper 的形式接入rpc客户端
+// 每次请求在健康节点（错误率低、区块落后不超过maxHeadLag）中随机取两个，选择延迟和错误率更低的一个；
+// 只读请求在网络错误、429或5xx时换节点重试，发送原始交易则先按交易哈希确认其他节点未收到该交易再重发。
+// 请求之间不保证发往同一节点：先查询最新区块号、再按该区块号查询时，后一个请求可能落到稍落后的节点上。
+// 因此区块、交易和收据查询在非领先节点上返回null时，会在健康检查中区块最高的节点上重试一次
+type EndpointPool struct {
+	endpoints  []*endpoint
+	transport  http.RoundTripper
+	maxHeadLag uint64
+
+	mu      sync.Mutex
+	highest uint64 // 所有节点中最高的区块号
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewEndpointPool 创建节点池，并按interval在后台对所有节点做健康检查
+// interval 为0时使用 DefaultHealthCheckInterval；发往各节点的请求经由transport，为nil时使用 http.DefaultTransport
+func NewEndpointPool(rpcURLs []string, interval time.Duration, transport http.RoundTripper) (*EndpointPool, error) {
+	if len(rpcURLs) == 0 {
+		return nil, errors.New("未配置RPC地址")
+	}
+
+	p := &EndpointPool{
+		transport:  transport,
+		maxHeadLag: DefaultMaxHeadLag,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, rpcURL := range rpcURLs {
+		u, err := url.Parse(rpcURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("节点池只支持http/https地址: %s", rpcURL)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{url: u})
+	}
+
+	if p.transport == nil {
+		p.transport = http.DefaultTransport
+	}
+	if interval <= 0 {
+		interval = DefaultHealthCheckInterval
+	}
+	p.checkAll()
+	go p.healthLoop(interval)
+
+	return p, nil
+}
+
+// Close 停止后台健康检查
+func (p *EndpointPool) Close() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+		<-p.done
+	}
+}
+
+// Status 返回所有节点的健康状态
+func (p *EndpointPool) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		status[i] = EndpointStatus{
+			URL:       e.url.Redacted(),
+			Latency:   e.latency,
+			ErrorRate: e.errorRate,
+			Head:      e.head,
+			HeadLag:   p.headLag(e),
+			Healthy:   p.healthy(e),
+		}
+	}
+	return status
+}
+
+// jsonrpcRequest JSON-RPC请求中用于路由的字段
+type jsonrpcRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// RoundTrip 实现 http.RoundTripper，按请求中的JSON-RPC方法选择节点并在需要时重试
+func (p *EndpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var call jsonrpcRequest
+	single := json.Unmarshal(body, &call) == nil
+
+	switch {
+	case single && call.Method == "eth_sendRawTransaction":
+		return p.sendRawTransaction(req, body, call)
+	case single && nonRetryableMethods[call.Method]:
+		return p.do(req, body, p.pick(nil))
+	case !single && batchContainsNonRetryable(body):
+		return p.do(req, body, p.pick(nil))
+	default:
+		return p.retry(req, body)
+	}
+}
+
+// retry 依次在不同节点上重试幂等请求，直到成功或所有节点都失败
+func (p *EndpointPool) retry(req *http.Request, body []byte) (*http.Response, error) {
+	tried := make(map[*endpoint]bool)
+	var (
+		resp *http.Response
+		err  error
+	)
+	for len(tried) < len(p.endpoints) {
+		e := p.pick(tried)
+		tried[e] = true
+
+		resp, err = p.do(req, body, e)
+		if !retryable(resp, err) {
+			if err == nil && headSensitive(body) {
+				return p.retryNullOnLeader(req, body, e, resp)
+			}
+			return resp, err
+		}
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+		if len(tried) < len(p.endpoints) && resp != nil {
+			drain(resp)
+		}
+	}
+	return resp, err
+}
+
+// retryNullOnLeader 区块或交易查询在非领先节点上返回null时，改到区块最高的节点上重试一次
+// 重试失败时仍返回原响应
+func (p *EndpointPool) retryNullOnLeader(req *http.Request, body []byte, e *endpoint, resp *http.Response) (*http.Response, error) {
+	leader := p.leader()
+	if leader == e || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if !hasNullResult(respBody) {
+		return resp, nil
+	}
+
+	retried, err := p.do(req, body, leader)
+	if err != nil {
+		return resp, nil
+	}
+	if retried.StatusCode != http.StatusOK {
+		drain(retried)
+		return resp, nil
+	}
+	return retried, nil
+}
+
+// sendRawTransaction 发送原始交易，换节点重发前先确认交易没有被之前的节点收到
+func (p *EndpointPool) sendRawTransaction(req *http.Request, body []byte, call jsonrpcRequest) (*http.Response, error) {
+	var raw hexutil.Bytes
+	if len(call.Params) != 1 || json.Unmarshal(call.Params[0], &raw) != nil {
+		return p.do(req, body, p.pick(nil))
+	}
+	hash := crypto.Keccak256Hash(raw).Hex()
+
+	tried := make(map[*endpoint]bool)
+	var (
+		resp *http.Response
+		err  error
+	)
+	for len(tried) < len(p.endpoints) {
+		e := p.pick(tried)
+		if len(tried) > 0 {
+			// 之前的节点可能已收到并广播了交易（例如响应在返回途中丢失），先查询再决定是否重发
+			if known, checkErr := p.transactionKnown(req.Context(), e, hash); checkErr == nil && known {
+				drain(resp)
+				return resultResponse(req, call.ID, hash)
+			}
+		}
+		tried[e] = true
+
+		if resp != nil {
+			drain(resp)
+		}
+		resp, err = p.do(req, body, e)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			return alreadyKnownAsSuccess(req, resp, call.ID, hash)
+		}
+		if !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+// transactionKnown 查询节点是否已有该交易（交易池或链上）
+func (p *EndpointPool) transactionKnown(ctx context.Context, e *endpoint, hash string) (bool, error) {
+	var tx json.RawMessage
+	if err := p.call(ctx, e, "eth_getTransactionByHash", &tx, hash); err != nil {
+		return false, err
+	}
+	return len(tx) > 0 && string(tx) != "null", nil
+}
+
+// alreadyKnownAsSuccess 节点返回"交易已存在"时，说明交易已经广播过，改写为发送成功
+func alreadyKnownAsSuccess(req *http.Request, resp *http.Response, id json.RawMessage, hash string) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &result) == nil && result.Error != nil {
+		msg := strings.ToLower(result.Error.Message)
+		if strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction") {
+			return resultResponse(req, id, hash)
+		}
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// do 将请求转发到指定节点并记录耗时和结果
+func (p *EndpointPool) do(req *http.Request, body []byte, e *endpoint) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.URL = e.url
+	out.Host = e.url.Host
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+
+	start := time.Now()
+	resp, err := p.transport.RoundTrip(out)
+	p.record(e, time.Since(start), !retryable(resp, err))
+	return resp, err
+}
+
+// call 直接向指定节点发送一次JSON-RPC调用，用于健康检查和交易查重
+func (p *EndpointPool) call(ctx context.Context, e *endpoint, method string, result interface{}, params ...interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.do(req, body, e)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("节点 %s 返回HTTP %d", e.url.Redacted(), resp.StatusCode)
+	}
+
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return err
+	}
+	if reply.Error != nil {
+		return errors.New(reply.Error.Message)
+	}
+	return json.Unmarshal(reply.Result, result)
+}
+
+// healthLoop 定期检查所有节点
+func (p *EndpointPool) healthLoop(interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.checkAll()
+		}
+	}
+}
+
+// checkAll 并发查询所有节点的最新区块号，更新延迟、错误率和区块落后情况
+func (p *EndpointPool) checkAll() {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+			defer cancel()
+
+			var head hexutil.Uint64
+			if err := p.call(ctx, e, "eth_blockNumber", &head); err != nil {
+				return
+			}
+
+			p.mu.Lock()
+			e.head = uint64(head)
+			p.highest = max(p.highest, e.head)
+			p.mu.Unlock()
+		}(e)
+	}
+	wg.Wait()
+}
+
+// record 更新节点的延迟和错误率
+func (p *EndpointPool) record(e *endpoint, elapsed time.Duration, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sample := 1.0
+	if ok {
+		sample = 0
+		if e.latency == 0 {
+			e.latency = elapsed
+		} else {
+			e.latency = time.Duration(ewmaWeight*float64(elapsed) + (1-ewmaWeight)*float64(e.latency))
+		}
+	}
+	e.errorRate = ewmaWeight*sample + (1-ewmaWeight)*e.errorRate
+}
+
+// pick 在未尝试过的节点中选择一个：随机取两个健康节点，返回得分更低的一个
+// 没有健康节点时在全部未尝试的节点中选择
+func (p *EndpointPool) pick(tried map[*endpoint]bool) *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var healthy, rest []*endpoint
+	for _, e := range p.endpoints {
+		switch {
+		case tried[e]:
+		case p.healthy(e):
+			healthy = append(healthy, e)
+		default:
+			rest = append(rest, e)
+		}
+	}
+
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = rest
+	}
+	if len(candidates) == 0 {
+		return p.endpoints[0]
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	i := rand.Intn(len(candidates))
+	j := rand.Intn(len(candidates) - 1)
+	if j >= i {
+		j++
+	}
+	if score(candidates[j]) < score(candidates[i]) {
+		return candidates[j]
+	}
+	return candidates[i]
+}
+
+// leader 返回最近一次健康检查中区块最高的节点，区块相同时选择得分更低的一个
+func (p *EndpointPool) leader() *endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	best := p.endpoints[0]
+	for _, e := range p.endpoints[1:] {
+		if e.head > best.head || (e.head == best.head && score(e) < score(best)) {
+			best = e
+		}
+	}
+	return best
+}
+
+// healthy 节点错误率低于阈值且区块落后不超过maxHeadLag，调用方需持有p.mu
+func (p *EndpointPool) healthy(e *endpoint) bool {
+	return e.errorRate < maxErrorRate && p.headLag(e) <= p.maxHeadLag
+}
+
+// headLag 节点落后于最高区块的数量，调用方需持有p.mu
+func (p *EndpointPool) headLag(e *endpoint) uint64 {
+	if e.head >= p.highest {
+		return 0
+	}
+	return p.highest - e.head
+}
+
+// score 节点得分，延迟越低、错误率越低得分越低
+func score(e *endpoint) float64 {
+	return float64(e.latency) * (1 + 4*e.errorRate)
+}
+
+// retryable 网络错误、429和5xx可以换节点重试
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// batchContainsNonRetryable 批量请求中是否包含不能换节点重试的方法
+func batchContainsNonRetryable(body []byte) bool {
+	var calls []jsonrpcRequest
+	if err := json.Unmarshal(body, &calls); err != nil {
+		return true
+	}
+	for _, call := range calls {
+		if call.Method == "eth_sendRawTransaction" || nonRetryableMethods[call.Method] {
+			return true
+		}
+	}
+	return false
+}
+
+// headSensitive 请求（或批量请求中的任一调用）是否为结果取决于节点区块高度的查询
+func headSensitive(body []byte) bool {
+	var call jsonrpcRequest
+	if json.Unmarshal(body, &call) == nil {
+		return headSensitiveMethods[call.Method]
+	}
+
+	var calls []jsonrpcRequest
+	if err := json.Unmarshal(body, &calls); err != nil {
+		return false
+	}
+	for _, call := range calls {
+		if headSensitiveMethods[call.Method] {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonrpcReply JSON-RPC响应中用于判断结果是否为null的字段
+type jsonrpcReply struct {
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// hasNullResult 响应（或批量响应中的任一调用）是否成功但结果为null
+func hasNullResult(body []byte) bool {
+	isNull := func(reply jsonrpcReply) bool {
+		return len(reply.Error) == 0 && (len(reply.Result) == 0 || string(reply.Result) == "null")
+	}
+
+	var reply jsonrpcReply
+	if json.Unmarshal(body, &reply) == nil {
+		return isNull(reply)
+	}
+
+	var replies []jsonrpcReply
+	if err := json.Unmarshal(body, &replies); err != nil {
+		return false
+	}
+	for _, reply := range replies {
+		if isNull(reply) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBody 读取请求体以便重试时重复发送
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// drain 丢弃并关闭不再使用的响应
+func drain(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// resultResponse 构造成功的JSON-RPC响应
+func resultResponse(req *http.Request, id json.RawMessage, result interface{}) (*http.Response, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"result":  result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
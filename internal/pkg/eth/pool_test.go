@@ -0,0 +1,452 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeMempool 多个假节点共享的交易池，模拟交易在节点间的传播
+type fakeMempool struct {
+	mu  sync.Mutex
+	txs map[string]bool
+}
+
+// fakeNode 基于httptest的假JSON-RPC节点
+type fakeNode struct {
+	*httptest.Server
+	mempool *fakeMempool
+
+	mu          sync.Mutex
+	head        uint64
+	status      int    // 非0时直接返回该HTTP状态码
+	retry       string // 返回status时附带的Retry-After响应头
+	dropSend    bool   // 收到交易后断开连接，模拟响应丢失
+	maxBatch    int    // 非0时拒绝超过该数量的批量请求
+	batchReject string // 拒绝批量请求的方式，见 serveBatch
+	// blockTxs 非nil时支持 eth_getBlockReceipts，按区块哈希给出区块内的交易哈希
+	blockTxs map[common.Hash][]common.Hash
+	calls    map[string]int
+	batches  []int // 收到的每个批量请求包含的调用数
+}
+
+func newFakeNode(t *testing.T, mempool *fakeMempool, head uint64) *fakeNode {
+	node := &fakeNode{mempool: mempool, head: head, calls: make(map[string]int)}
+	node.Server = httptest.NewServer(http.HandlerFunc(node.serve))
+	t.Cleanup(node.Close)
+	return node
+}
+
+func (n *fakeNode) serve(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+
+	var reqs []jsonrpcRequest
+	if batch {
+		err = json.Unmarshal(body, &reqs)
+	} else {
+		reqs = make([]jsonrpcRequest, 1)
+		err = json.Unmarshal(body, &reqs[0])
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	for _, req := range reqs {
+		n.calls[req.Method]++
+	}
+	if batch {
+		n.batches = append(n.batches, len(reqs))
+	}
+	status, retry, dropSend := n.status, n.retry, n.dropSend
+	n.mu.Unlock()
+
+	if status != 0 {
+		if retry != "" {
+			w.Header().Set("Retry-After", retry)
+		}
+		w.WriteHeader(status)
+		return
+	}
+
+	if batch {
+		n.serveBatch(w, reqs)
+		return
+	}
+
+	reply := n.reply(reqs[0])
+	if dropSend && reqs[0].Method == "eth_sendRawTransaction" {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// serveBatch 处理批量请求，超过maxBatch时按batchReject拒绝：
+// "413" 返回HTTP 413，"truncate" 只响应前maxBatch个调用，其他值与geth一致只对第一个调用返回错误
+func (n *fakeNode) serveBatch(w http.ResponseWriter, reqs []jsonrpcRequest) {
+	n.mu.Lock()
+	maxBatch, reject := n.maxBatch, n.batchReject
+	n.mu.Unlock()
+
+	var replies []map[string]interface{}
+	switch {
+	case maxBatch == 0 || len(reqs) <= maxBatch:
+		for _, req := range reqs {
+			replies = append(replies, n.reply(req))
+		}
+	case reject == "413":
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	case reject == "truncate":
+		for _, req := range reqs[:maxBatch] {
+			replies = append(replies, n.reply(req))
+		}
+	default:
+		replies = append(replies, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      reqs[0].ID,
+			"error":   map[string]interface{}{"code": -32600, "message": "batch too large"},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(replies)
+}
+
+// reply 处理单个调用并返回响应
+func (n *fakeNode) reply(req jsonrpcRequest) map[string]interface{} {
+	n.mu.Lock()
+	head, blockTxs := n.head, n.blockTxs
+	n.mu.Unlock()
+
+	reply := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch req.Method {
+	case "eth_chainId":
+		reply["result"] = "0x539"
+	case "eth_blockNumber":
+		reply["result"] = hexutil.Uint64(head)
+	case "eth_getBlockByNumber":
+		var number hexutil.Uint64
+		json.Unmarshal(req.Params[0], &number)
+		if uint64(number) > head {
+			reply["result"] = nil
+		} else {
+			reply["result"] = fakeBlock(uint64(number))
+		}
+	case "eth_getTransactionReceipt":
+		var hash common.Hash
+		json.Unmarshal(req.Params[0], &hash)
+		reply["result"] = fakeReceipt(hash)
+	case "eth_getBlockReceipts":
+		var block rpc.BlockNumberOrHash
+		json.Unmarshal(req.Params[0], &block)
+		hash, _ := block.Hash()
+		if blockTxs == nil {
+			reply["error"] = map[string]interface{}{"code": -32601, "message": "the method eth_getBlockReceipts does not exist/is not available"}
+			break
+		}
+		receipts := []*types.Receipt{}
+		for _, txHash := range blockTxs[hash] {
+			receipts = append(receipts, fakeReceipt(txHash))
+		}
+		reply["result"] = receipts
+	case "eth_sendRawTransaction":
+		var raw hexutil.Bytes
+		json.Unmarshal(req.Params[0], &raw)
+		hash := crypto.Keccak256Hash(raw).Hex()
+
+		n.mempool.mu.Lock()
+		known := n.mempool.txs[hash]
+		n.mempool.txs[hash] = true
+		n.mempool.mu.Unlock()
+
+		if known {
+			reply["error"] = map[string]interface{}{"code": -32000, "message": "already known"}
+		} else {
+			reply["result"] = hash
+		}
+	case "eth_getTransactionByHash":
+		var hash string
+		json.Unmarshal(req.Params[0], &hash)
+
+		n.mempool.mu.Lock()
+		known := n.mempool.txs[hash]
+		n.mempool.mu.Unlock()
+
+		if known {
+			// 只需要非null即可表示节点已有该交易
+			reply["result"] = map[string]string{"hash": hash}
+		} else {
+			reply["result"] = nil
+		}
+	default:
+		reply["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+	}
+	return reply
+}
+
+// fakeBlock 构造指定高度的空区块，格式与 eth_getBlockByNumber 的返回值一致
+func fakeBlock(number uint64) map[string]interface{} {
+	header := &types.Header{
+		Number:      new(big.Int).SetUint64(number),
+		Difficulty:  big.NewInt(0),
+		UncleHash:   types.EmptyUncleHash,
+		TxHash:      types.EmptyTxsHash,
+		ReceiptHash: types.EmptyReceiptsHash,
+		GasLimit:    30_000_000,
+		Time:        number * 12,
+	}
+	raw, _ := json.Marshal(header)
+	var block map[string]interface{}
+	json.Unmarshal(raw, &block)
+	block["transactions"] = []interface{}{}
+	return block
+}
+
+// fakeReceipt 构造交易的成功收据
+func fakeReceipt(hash common.Hash) *types.Receipt {
+	return &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		Logs:              []*types.Log{},
+		TxHash:            hash,
+		GasUsed:           21000,
+		EffectiveGasPrice: big.NewInt(1),
+		BlockNumber:       big.NewInt(1),
+	}
+}
+
+func (n *fakeNode) set(f func(n *fakeNode)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	f(n)
+}
+
+func (n *fakeNode) count(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+// batchSizes 返回收到的批量请求大小，按收到的顺序
+func (n *fakeNode) batchSizes() []int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]int(nil), n.batches...)
+}
+
+// newPoolClient 创建经由节点池连接假节点的客户端，健康检查只在创建时执行一次，单个节点上不重试
+func newPoolClient(t *testing.T, nodes ...*fakeNode) *Client {
+	t.Helper()
+	urls := make([]string, len(nodes))
+	for i, node := range nodes {
+		urls[i] = node.URL
+	}
+
+	client, err := newPooledClient(urls, time.Hour, RetryPolicy{})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestPoolFailsOverReads(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	healthy := newFakeNode(t, mempool, 100)
+	limited := newFakeNode(t, mempool, 100)
+	client := newPoolClient(t, healthy, limited)
+
+	limited.set(func(n *fakeNode) { n.status = http.StatusTooManyRequests })
+	before := limited.count("eth_blockNumber")
+
+	for i := 0; i < 10; i++ {
+		head, err := client.GetLatestBlockNumber(context.Background())
+		if err != nil {
+			t.Fatalf("第%d次查询失败: %v", i, err)
+		}
+		if head != 100 {
+			t.Fatalf("head = %d, want 100", head)
+		}
+	}
+
+	// 失败一次后错误率上升，之后的请求优先发往另一个节点
+	if got := limited.count("eth_blockNumber") - before; got > 1 {
+		t.Errorf("返回429的节点收到了 %d 次请求, want <= 1", got)
+	}
+}
+
+func TestPoolAvoidsLaggingEndpoint(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	fresh := newFakeNode(t, mempool, 100)
+	lagging := newFakeNode(t, mempool, 90)
+	client := newPoolClient(t, fresh, lagging)
+
+	before := lagging.count("eth_blockNumber")
+	for i := 0; i < 10; i++ {
+		if _, err := client.GetLatestBlockNumber(context.Background()); err != nil {
+			t.Fatalf("查询失败: %v", err)
+		}
+	}
+	if got := lagging.count("eth_blockNumber") - before; got != 0 {
+		t.Errorf("落后10个区块的节点收到了 %d 次请求, want 0", got)
+	}
+
+	for _, status := range client.Endpoints() {
+		if status.URL == lagging.URL && (status.Healthy || status.HeadLag != 10) {
+			t.Errorf("落后节点状态 = %+v, want 不健康且落后10个区块", status)
+		}
+	}
+}
+
+func TestPoolRetriesNotFoundOnLeader(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	leader := newFakeNode(t, mempool, 10)
+	behind := newFakeNode(t, mempool, 8)
+	client := newPoolClient(t, leader, behind)
+
+	// 落后2个区块仍算健康；让落后节点延迟更低，普通请求都优先发往它
+	client.pool.mu.Lock()
+	for _, e := range client.pool.endpoints {
+		e.latency = time.Millisecond
+		if e.url.String() == leader.URL {
+			e.latency = time.Second
+		}
+	}
+	client.pool.mu.Unlock()
+
+	ctx := context.Background()
+	block, err := client.GetBlockByNumber(ctx, 10)
+	if err != nil {
+		t.Fatalf("查询领先节点上才有的区块失败: %v", err)
+	}
+	if block.Number != 10 {
+		t.Errorf("区块号 = %d, want 10", block.Number)
+	}
+	if behind.count("eth_getBlockByNumber") != 1 || leader.count("eth_getBlockByNumber") != 1 {
+		t.Errorf("落后节点收到 %d 次、领先节点收到 %d 次查询, want 各1次",
+			behind.count("eth_getBlockByNumber"), leader.count("eth_getBlockByNumber"))
+	}
+
+	// 批量请求中只要有一个区块为null就整体改发领先节点
+	blocks, err := client.GetBlocksRange(ctx, 8, 10)
+	if err != nil {
+		t.Fatalf("批量查询失败: %v", err)
+	}
+	if len(blocks) != 3 || blocks[2].NumberU64() != 10 {
+		t.Errorf("批量查询返回 %d 个区块", len(blocks))
+	}
+
+	// 已在落后节点上找到的区块不再发往领先节点
+	before := leader.count("eth_getBlockByNumber")
+	if _, err := client.GetBlockByNumber(ctx, 8); err != nil {
+		t.Fatalf("查询区块8失败: %v", err)
+	}
+	if got := leader.count("eth_getBlockByNumber") - before; got != 0 {
+		t.Errorf("领先节点收到 %d 次查询, want 0", got)
+	}
+
+	// 所有节点都没有的区块仍返回 ErrNotFound
+	if _, err := client.GetBlockByNumber(ctx, 11); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPoolAllEndpointsDown(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	a := newFakeNode(t, mempool, 100)
+	b := newFakeNode(t, mempool, 100)
+	client := newPoolClient(t, a, b)
+
+	a.set(func(n *fakeNode) { n.status = http.StatusServiceUnavailable })
+	b.set(func(n *fakeNode) { n.status = http.StatusBadGateway })
+
+	_, err := client.GetLatestBlockNumber(context.Background())
+	if !errors.Is(err, ErrRPCUnavailable) {
+		t.Fatalf("err = %v, want ErrRPCUnavailable", err)
+	}
+	if a.count("eth_blockNumber") < 2 || b.count("eth_blockNumber") < 2 {
+		t.Errorf("每个节点都应被尝试: a=%d b=%d", a.count("eth_blockNumber"), b.count("eth_blockNumber"))
+	}
+}
+
+// signedTestTx 构造一笔已签名的交易
+func signedTestTx(t *testing.T) *types.Transaction {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	to := crypto.PubkeyToAddress(key.PublicKey)
+	tx := types.NewTx(&types.LegacyTx{Nonce: 0, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1)})
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(1337)), key)
+	if err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	return signed
+}
+
+func TestPoolDoesNotResendDeliveredTransaction(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	a := newFakeNode(t, mempool, 100)
+	b := newFakeNode(t, mempool, 100)
+	client := newPoolClient(t, a, b)
+
+	// 两个节点都会收下交易但断开连接，无论先选中哪个，第二个节点都应通过查询发现交易已存在
+	a.set(func(n *fakeNode) { n.dropSend = true })
+	b.set(func(n *fakeNode) { n.dropSend = true })
+
+	tx := signedTestTx(t)
+	if err := client.Client.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+
+	if sends := a.count("eth_sendRawTransaction") + b.count("eth_sendRawTransaction"); sends != 1 {
+		t.Errorf("交易被发送了 %d 次, want 1", sends)
+	}
+}
+
+func TestPoolResendsUndeliveredTransaction(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	a := newFakeNode(t, mempool, 100)
+	b := newFakeNode(t, mempool, 100)
+	client := newPoolClient(t, a, b)
+
+	// 其中一个节点在收下交易前就失败，交易应改由另一个节点发送
+	a.set(func(n *fakeNode) { n.status = http.StatusServiceUnavailable })
+
+	tx := signedTestTx(t)
+	if err := client.Client.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	if b.count("eth_sendRawTransaction") != 1 {
+		t.Errorf("可用节点收到 %d 次发送, want 1", b.count("eth_sendRawTransaction"))
+	}
+	if !mempool.txs[tx.Hash().Hex()] {
+		t.Error("交易未进入交易池")
+	}
+}
@@ -0,0 +1,288 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-eth-backend/internal/pkg/config"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultMaxRetries 可重试错误的默认最大重试次数
+	DefaultMaxRetries = 3
+	// DefaultMinBackoff 第一次重试前的默认等待时间，之后每次翻倍
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff 重试等待时间的默认上限（不限制节点通过Retry-After要求的等待时间）
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy 对单个节点的限流与重试策略
+type RetryPolicy struct {
+	RequestsPerSecond float64       // 令牌桶每秒补充的令牌数，0表示不限流
+	Burst             int           // 令牌桶容量
+	MaxRetries        int           // 可重试错误的最大重试次数，0表示不重试
+	MinBackoff        time.Duration // 第一次重试前的等待时间
+	MaxBackoff        time.Duration // 重试等待时间上限
+}
+
+// DefaultRetryPolicy 返回不限流、按默认退避重试的策略
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// NewRetryPolicy 根据网络的 rate_limit 配置创建策略，未配置的项使用默认值
+func NewRetryPolicy(cfg config.RateLimitConfig) (RetryPolicy, error) {
+	policy := DefaultRetryPolicy()
+	policy.RequestsPerSecond = cfg.RequestsPerSecond
+	policy.Burst = cfg.Burst
+	if cfg.MaxRetries != nil {
+		policy.MaxRetries = *cfg.MaxRetries
+	}
+
+	minBackoff, err := cfg.GetMinBackoff()
+	if err != nil {
+		return policy, err
+	}
+	if minBackoff > 0 {
+		policy.MinBackoff = minBackoff
+	}
+
+	maxBackoff, err := cfg.GetMaxBackoff()
+	if err != nil {
+		return policy, err
+	}
+	if maxBackoff > 0 {
+		policy.MaxBackoff = maxBackoff
+	}
+
+	if policy.RequestsPerSecond > 0 && policy.Burst <= 0 {
+		policy.Burst = 1
+	}
+	return policy, nil
+}
+
+// backoff 返回第attempt次重试（从0开始）前的等待时间：指数退避并加入随机抖动
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.MinBackoff
+	for i := 0; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	// 一半固定、一半随机，避免多个客户端同时重试
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// limitOnly 只限流不重试的策略，用于节点池中的单个节点，失败时由节点池先换节点
+func (p RetryPolicy) limitOnly() RetryPolicy {
+	p.MaxRetries = 0
+	return p
+}
+
+// retryOnly 只重试不限流的策略，用于节点池之外，所有节点都失败后再整体退避重试
+func (p RetryPolicy) retryOnly() RetryPolicy {
+	p.RequestsPerSecond, p.Burst = 0, 0
+	return p
+}
+
+// RateLimitTransport 包装 http.RoundTripper：按节点地址做令牌桶限流，
+// 并对限流（HTTP 429、JSON-RPC -32005）、5xx和网络错误按策略退避重试，优先遵循节点返回的Retry-After
+// 节点服务商按调用次数计量，批量请求中的每个调用各消耗一个令牌；
+// 发送交易等不能重复执行的请求只在明确被限流（请求未被处理）时重试
+type RateLimitTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimitTransport 创建限流重试中间件，next为nil时使用 http.DefaultTransport
+func NewRateLimitTransport(next http.RoundTripper, policy RetryPolicy) *RateLimitTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RateLimitTransport{
+		next:     next,
+		policy:   policy,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	idempotent := isIdempotent(body)
+	calls := callCount(body)
+	limiter := t.limiter(req.URL.String())
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := waitTokens(ctx, limiter, calls); err != nil {
+			return nil, err
+		}
+
+		out := req.Clone(ctx)
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+
+		resp, err := t.next.RoundTrip(out)
+		retry, retryAfter, err := shouldRetry(resp, err, idempotent)
+		if !retry || attempt >= t.policy.MaxRetries {
+			return resp, err
+		}
+
+		delay := max(t.policy.backoff(attempt), retryAfter)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// 等不到下一次重试，直接返回本次结果
+			return resp, err
+		}
+		drain(resp)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// limiter 返回节点的令牌桶，未配置限流时返回nil
+func (t *RateLimitTransport) limiter(endpoint string) *rate.Limiter {
+	if t.policy.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limiter, ok := t.limiters[endpoint]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(t.policy.RequestsPerSecond), t.policy.Burst)
+		t.limiters[endpoint] = limiter
+	}
+	return limiter
+}
+
+// waitTokens 等待令牌桶中有n个令牌，n超过桶容量时分批等待
+func waitTokens(ctx context.Context, limiter *rate.Limiter, n int) error {
+	if limiter == nil {
+		return nil
+	}
+	for n > 0 {
+		batch := min(n, limiter.Burst())
+		if err := limiter.WaitN(ctx, batch); err != nil {
+			return err
+		}
+		n -= batch
+	}
+	return nil
+}
+
+// callCount 请求包含的JSON-RPC调用数，单个调用（或无法解析的请求体）为1
+func callCount(body []byte) int {
+	var calls []json.RawMessage
+	if err := json.Unmarshal(body, &calls); err != nil || len(calls) == 0 {
+		return 1
+	}
+	return len(calls)
+}
+
+// shouldRetry 判断响应是否可重试，并返回节点要求的最短等待时间
+// 检查JSON-RPC错误码时会读取响应体，返回的响应体可以照常读取
+func shouldRetry(resp *http.Response, err error, idempotent bool) (bool, time.Duration, error) {
+	if err != nil {
+		return idempotent, 0, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true, retryAfter(resp), nil
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return idempotent, retryAfter(resp), nil
+	case resp.StatusCode != http.StatusOK:
+		return false, 0, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return idempotent, 0, err
+	}
+	// 批量请求中只有部分被限流时，重发整批会重复执行其余请求
+	some, all := limitExceeded(body)
+	return all || (some && idempotent), retryAfter(resp), nil
+}
+
+// limitExceeded 检查响应中的 -32005 限额超出错误，分别返回是否有、是否全部为该错误（单个响应视为一批）
+func limitExceeded(body []byte) (some, all bool) {
+	if !bytes.Contains(body, []byte(strconv.Itoa(rpcCodeLimitExceeded))) {
+		return false, false
+	}
+
+	type reply struct {
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	var replies []reply
+	if err := json.Unmarshal(body, &replies); err != nil {
+		var single reply
+		if err := json.Unmarshal(body, &single); err != nil {
+			return false, false
+		}
+		replies = []reply{single}
+	}
+
+	all = len(replies) > 0
+	for _, r := range replies {
+		if r.Error != nil && r.Error.Code == rpcCodeLimitExceeded {
+			some = true
+		} else {
+			all = false
+		}
+	}
+	return some, all
+}
+
+// retryAfter 解析Retry-After响应头（秒数或HTTP日期），没有时返回0
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
+
+// isIdempotent 请求是否可以安全地重复发送（不含发送交易和过滤器方法）
+func isIdempotent(body []byte) bool {
+	var call jsonrpcRequest
+	if err := json.Unmarshal(body, &call); err == nil {
+		return call.Method != "eth_sendRawTransaction" && !nonRetryableMethods[call.Method]
+	}
+	return !batchContainsNonRetryable(body)
+}
@@ -0,0 +1,265 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond, 200 * time.Millisecond},
+		{2, 200 * time.Millisecond, 400 * time.Millisecond},
+		{3, 400 * time.Millisecond, 800 * time.Millisecond},
+		{4, 500 * time.Millisecond, time.Second}, // 达到上限
+		{20, 500 * time.Millisecond, time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			if d := policy.backoff(tt.attempt); d < tt.min || d > tt.max {
+				t.Fatalf("backoff(%d) = %v, want [%v, %v]", tt.attempt, d, tt.min, tt.max)
+			}
+		}
+	}
+
+	if d := (RetryPolicy{}).backoff(3); d != 0 {
+		t.Errorf("未配置退避时 backoff = %v, want 0", d)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		min, max time.Duration
+	}{
+		{"未设置", "", 0, 0},
+		{"秒数", "3", 3 * time.Second, 3 * time.Second},
+		{"零秒", "0", 0, 0},
+		{"负数", "-5", 0, 0},
+		{"无法解析", "soon", 0, 0},
+		{"HTTP日期", time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), 8 * time.Second, 10 * time.Second},
+		{"过去的HTTP日期", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.value != "" {
+				resp.Header.Set("Retry-After", tt.value)
+			}
+			if d := retryAfter(resp); d < tt.min || d > tt.max {
+				t.Errorf("retryAfter(%q) = %v, want [%v, %v]", tt.value, d, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestLimitExceeded(t *testing.T) {
+	const (
+		limited = `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`
+		other   = `{"jsonrpc":"2.0","id":2,"error":{"code":-32000,"message":"header not found"}}`
+		success = `{"jsonrpc":"2.0","id":3,"result":"0x1"}`
+	)
+	tests := []struct {
+		name      string
+		body      string
+		some, all bool
+	}{
+		{"单个限流", limited, true, true},
+		{"单个其他错误", other, false, false},
+		{"单个成功", success, false, false},
+		{"整批限流", "[" + limited + "," + limited + "]", true, true},
+		{"部分限流", "[" + limited + "," + success + "]", true, false},
+		{"批量无限流", "[" + other + "," + success + "]", false, false},
+		{"空批量", "[]", false, false},
+		{"无法解析", `limit -32005 exceeded`, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			some, all := limitExceeded([]byte(tt.body))
+			if some != tt.some || all != tt.all {
+				t.Errorf("limitExceeded = %v/%v, want %v/%v", some, all, tt.some, tt.all)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	const (
+		limited = `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"limit exceeded"}}`
+		success = `{"jsonrpc":"2.0","id":2,"result":"0x1"}`
+	)
+	response := func(status int, body, retry string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+		if retry != "" {
+			resp.Header.Set("Retry-After", retry)
+		}
+		return resp
+	}
+	netErr := errors.New("connection reset by peer")
+
+	tests := []struct {
+		name       string
+		resp       *http.Response
+		err        error
+		idempotent bool
+		retry      bool
+		after      time.Duration
+	}{
+		{"网络错误可重复", nil, netErr, true, true, 0},
+		{"网络错误不可重复", nil, netErr, false, false, 0},
+		{"429不可重复也重试", response(http.StatusTooManyRequests, "", "2"), nil, false, true, 2 * time.Second},
+		{"503可重复", response(http.StatusServiceUnavailable, "", "1"), nil, true, true, time.Second},
+		{"503不可重复", response(http.StatusServiceUnavailable, "", ""), nil, false, false, 0},
+		{"400", response(http.StatusBadRequest, "", ""), nil, true, false, 0},
+		{"200成功", response(http.StatusOK, success, ""), nil, true, false, 0},
+		{"200限流", response(http.StatusOK, limited, ""), nil, false, true, 0},
+		{"部分限流可重复", response(http.StatusOK, "["+limited+","+success+"]", ""), nil, true, true, 0},
+		{"部分限流不可重复", response(http.StatusOK, "["+limited+","+success+"]", ""), nil, false, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body string
+			if tt.resp != nil && tt.resp.StatusCode == http.StatusOK {
+				data, _ := io.ReadAll(tt.resp.Body)
+				body = string(data)
+				tt.resp.Body = io.NopCloser(strings.NewReader(body))
+			}
+
+			retry, after, err := shouldRetry(tt.resp, tt.err, tt.idempotent)
+			if retry != tt.retry || after != tt.after || err != tt.err {
+				t.Errorf("shouldRetry = %v, %v, %v, want %v, %v, %v", retry, after, err, tt.retry, tt.after, tt.err)
+			}
+			// 检查过的响应体仍可照常读取
+			if body != "" {
+				if data, _ := io.ReadAll(tt.resp.Body); string(data) != body {
+					t.Errorf("响应体 = %q, want %q", data, body)
+				}
+			}
+		})
+	}
+}
+
+// countingTransport 记录请求次数并返回成功响应
+type countingTransport struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.calls++
+	t.mu.Unlock()
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))}, nil
+}
+
+func TestRateLimitTransportChargesPerCall(t *testing.T) {
+	transport := NewRateLimitTransport(&countingTransport{}, RetryPolicy{RequestsPerSecond: 1000, Burst: 10})
+	send := func(body string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, "http://node.invalid", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("创建请求失败: %v", err)
+		}
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip失败: %v", err)
+		}
+		drain(resp)
+	}
+	batch := func(n int) string {
+		calls := make([]string, n)
+		for i := range calls {
+			calls[i] = `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+		}
+		return "[" + strings.Join(calls, ",") + "]"
+	}
+
+	limiter := transport.limiter("http://node.invalid")
+	send(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	if tokens := limiter.Tokens(); tokens > 9.5 {
+		t.Errorf("单个调用后剩余令牌 = %.1f, want 约9", tokens)
+	}
+
+	// 10个调用的批量请求消耗整桶令牌
+	time.Sleep(20 * time.Millisecond)
+	send(batch(10))
+	if tokens := limiter.Tokens(); tokens > 1 {
+		t.Errorf("10个调用的批量请求后剩余令牌 = %.1f, want 约0", tokens)
+	}
+
+	// 超过桶容量的批量请求分批等待令牌，而不是直接失败
+	start := time.Now()
+	send(batch(25))
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("25个调用的批量请求耗时 %v, 应等待补充令牌", elapsed)
+	}
+}
+
+func TestPoolFailsOverBeforeHonouringRetryAfter(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	healthy := newFakeNode(t, mempool, 100)
+	limited := newFakeNode(t, mempool, 100)
+
+	urls := []string{healthy.URL, limited.URL}
+	policy := RetryPolicy{MaxRetries: 3, MinBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+	client, err := newPooledClient(urls, time.Hour, policy)
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	// 被限流的节点要求等待30秒，节点池应先换节点而不是在该节点上等待
+	limited.set(func(n *fakeNode) {
+		n.status = http.StatusTooManyRequests
+		n.retry = "30"
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetLatestBlockNumber(ctx); err != nil {
+			t.Fatalf("查询失败: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("查询耗时 %v, 应立即换节点", elapsed)
+	}
+}
+
+func TestPoolRetriesAfterAllEndpointsLimited(t *testing.T) {
+	mempool := &fakeMempool{txs: make(map[string]bool)}
+	a := newFakeNode(t, mempool, 100)
+	b := newFakeNode(t, mempool, 100)
+
+	policy := RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	client, err := newPooledClient([]string{a.URL, b.URL}, time.Hour, policy)
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	for _, node := range []*fakeNode{a, b} {
+		node.set(func(n *fakeNode) { n.status = http.StatusTooManyRequests })
+	}
+	beforeA, beforeB := a.count("eth_blockNumber"), b.count("eth_blockNumber")
+
+	if _, err := client.GetLatestBlockNumber(context.Background()); !errors.Is(err, ErrRPCUnavailable) {
+		t.Fatalf("err = %v, want ErrRPCUnavailable", err)
+	}
+	// 每轮节点池依次尝试两个节点，所有节点都失败后整体重试 MaxRetries 次
+	if gotA, gotB := a.count("eth_blockNumber")-beforeA, b.count("eth_blockNumber")-beforeB; gotA != 3 || gotB != 3 {
+		t.Errorf("节点收到的请求 = %d/%d, want 3/3", gotA, gotB)
+	}
+}
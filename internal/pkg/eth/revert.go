@@ -0,0 +1,181 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// errorSelector Error(string) 的函数选择器
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	// panicSelector Panic(uint256) 的函数选择器
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+	// uint256Type Panic(uint256) 的参数类型
+	uint256Type, _ = abi.NewType("uint256", "", nil)
+)
+
+// errorLookup 按选择器查找合约自定义错误
+type errorLookup func(selector [4]byte) (abi.Error, bool)
+
+// panicReasons Solidity Panic(uint256) 错误码的含义
+var panicReasons = map[uint64]string{
+	0x00: "编译器插入的通用panic",
+	0x01: "assert断言失败",
+	0x11: "算术运算上溢或下溢",
+	0x12: "除以零或对零取模",
+	0x21: "转换为枚举类型时数值越界",
+	0x22: "访问了编码错误的storage字节数组",
+	0x31: "对空数组调用pop()",
+	0x32: "数组或bytesN越界访问",
+	0x41: "分配内存过多或创建了过大的数组",
+	0x51: "调用了未初始化的内部函数变量",
+}
+
+// RegisterErrorABI 注册合约ABI中定义的自定义错误，用于解码回滚原因
+func (c *Client) RegisterErrorABI(contractABI abi.ABI) {
+	c.errorsMu.Lock()
+	defer c.errorsMu.Unlock()
+
+	for _, customErr := range contractABI.Errors {
+		var selector [4]byte
+		copy(selector[:], customErr.ID[:4])
+		c.customErrors[selector] = customErr
+	}
+}
+
+// lookupError 按选择器查找已注册的自定义错误
+func (c *Client) lookupError(selector [4]byte) (abi.Error, bool) {
+	c.errorsMu.RLock()
+	defer c.errorsMu.RUnlock()
+
+	customErr, ok := c.customErrors[selector]
+	return customErr, ok
+}
+
+// wrapCallError 与wrapError相同，并使用已注册的自定义错误解码回滚原因
+func (c *Client) wrapCallError(err error, format string, args ...any) error {
+	return wrapErrorWith(err, c.lookupError, format, args...)
+}
+
+// RevertReason 获取失败交易的回滚原因
+// 在交易所在区块的父区块状态上以eth_call重放交易，解码 Error(string)、Panic(uint256)
+// 以及通过 RegisterErrorABI 注册的自定义错误；若交易失败依赖同区块内先前交易的状态，重放结果可能不同
+func (c *Client) RevertReason(ctx context.Context, txHash string) (*RevertError, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	hash := common.HexToHash(txHash)
+	tx, _, err := c.Client.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, wrapError(err, "获取交易 %s 失败", txHash)
+	}
+	receipt, err := c.Client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, wrapError(err, "获取交易收据 %s 失败", txHash)
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("交易 %s 执行成功，没有回滚原因", txHash)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("恢复交易 %s 的发送方失败: %w", txHash, err)
+	}
+
+	// 不携带费用字段，避免父区块的baseFee校验和余额校验干扰重放结果
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         tx.To(),
+		Gas:        tx.Gas(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+
+	_, err = c.Client.CallContract(ctx, msg, parent)
+	if err == nil {
+		return nil, fmt.Errorf("交易 %s 在父区块上重放未回滚，失败可能依赖同区块内先前交易的状态", txHash)
+	}
+
+	wrapped := c.wrapCallError(err, "重放交易 %s 失败", txHash)
+	var revert *RevertError
+	if !errors.As(wrapped, &revert) {
+		return nil, wrapped
+	}
+
+	return revert, nil
+}
+
+// FailureReason 返回失败交易的可读失败原因，用于日志和命令行输出；无法获取回滚原因时返回获取失败的说明
+func (c *Client) FailureReason(ctx context.Context, txHash string) string {
+	revert, err := c.RevertReason(ctx, txHash)
+	if err != nil {
+		return fmt.Sprintf("无法获取失败原因: %v", err)
+	}
+	return revert.Error()
+}
+
+// decode 解码回滚数据；lookup非nil时用于查找自定义错误
+func (e *RevertError) decode(lookup errorLookup) {
+	if len(e.Data) < 4 {
+		return
+	}
+
+	switch {
+	case bytes.Equal(e.Data[:4], errorSelector):
+		if reason, err := abi.UnpackRevert(e.Data); err == nil {
+			e.Reason = reason
+		}
+	case bytes.Equal(e.Data[:4], panicSelector):
+		unpacked, err := (abi.Arguments{{Type: uint256Type}}).Unpack(e.Data[4:])
+		if err != nil {
+			return
+		}
+		e.PanicCode = unpacked[0].(*big.Int)
+		e.Reason = panicReason(e.PanicCode)
+	case lookup != nil:
+		var selector [4]byte
+		copy(selector[:], e.Data[:4])
+		customErr, ok := lookup(selector)
+		if !ok {
+			return
+		}
+		args, err := customErr.Inputs.Unpack(e.Data[4:])
+		if err != nil {
+			return
+		}
+		e.ErrorName = customErr.Name
+		e.ErrorArgs = args
+		e.Reason = formatCustomError(customErr.Name, args)
+	}
+}
+
+// panicReason 返回Panic错误码的可读说明
+func panicReason(code *big.Int) string {
+	if code.IsUint64() {
+		if reason, ok := panicReasons[code.Uint64()]; ok {
+			return fmt.Sprintf("panic %#x: %s", code, reason)
+		}
+	}
+	return fmt.Sprintf("panic %#x: 未知的错误码", code)
+}
+
+// formatCustomError 将自定义错误格式化为 Name(arg1, arg2) 形式
+func formatCustomError(name string, args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprint(arg)
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(parts, ", "))
+}
@@ -0,0 +1,173 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// revertTestABI 带自定义错误的合约ABI
+const revertTestABI = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`
+
+// revertData 按选择器和参数编码回滚数据
+func revertData(t *testing.T, selector []byte, types []string, args ...interface{}) []byte {
+	t.Helper()
+
+	arguments := make(abi.Arguments, len(types))
+	for i, name := range types {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatalf("创建ABI类型 %s 失败: %v", name, err)
+		}
+		arguments[i] = abi.Argument{Type: typ}
+	}
+	packed, err := arguments.Pack(args...)
+	if err != nil {
+		t.Fatalf("编码回滚数据失败: %v", err)
+	}
+	return append(append([]byte{}, selector...), packed...)
+}
+
+func TestRevertErrorDecode(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(revertTestABI))
+	if err != nil {
+		t.Fatalf("解析ABI失败: %v", err)
+	}
+	customErr := contractABI.Errors["InsufficientBalance"]
+	lookup := func(selector [4]byte) (abi.Error, bool) {
+		if selector == [4]byte(customErr.ID[:4]) {
+			return customErr, true
+		}
+		return abi.Error{}, false
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		lookup    errorLookup
+		reason    string
+		panicCode int64
+		errorName string
+	}{
+		{
+			name:   "Error(string)",
+			data:   revertData(t, errorSelector, []string{"string"}, "Cannot decrement below zero"),
+			reason: "Cannot decrement below zero",
+		},
+		{
+			name:      "算术溢出",
+			data:      revertData(t, panicSelector, []string{"uint256"}, big.NewInt(0x11)),
+			reason:    "panic 0x11: 算术运算上溢或下溢",
+			panicCode: 0x11,
+		},
+		{
+			name:      "未知Panic错误码",
+			data:      revertData(t, panicSelector, []string{"uint256"}, big.NewInt(0x99)),
+			reason:    "panic 0x99: 未知的错误码",
+			panicCode: 0x99,
+		},
+		{
+			name:      "自定义错误",
+			data:      revertData(t, customErr.ID[:4], []string{"uint256", "uint256"}, big.NewInt(1), big.NewInt(2)),
+			lookup:    lookup,
+			reason:    "InsufficientBalance(1, 2)",
+			errorName: "InsufficientBalance",
+		},
+		{
+			name: "未注册的自定义错误",
+			data: revertData(t, customErr.ID[:4], []string{"uint256", "uint256"}, big.NewInt(1), big.NewInt(2)),
+		},
+		{
+			name: "数据不足4字节",
+			data: []byte{0x08, 0xc3},
+		},
+		{
+			name: "Error(string)数据损坏",
+			data: append(append([]byte{}, errorSelector...), 0x01),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revert := &RevertError{Data: tt.data}
+			revert.decode(tt.lookup)
+
+			if revert.Reason != tt.reason {
+				t.Errorf("Reason = %q, want %q", revert.Reason, tt.reason)
+			}
+			if tt.panicCode != 0 && (revert.PanicCode == nil || revert.PanicCode.Int64() != tt.panicCode) {
+				t.Errorf("PanicCode = %v, want %#x", revert.PanicCode, tt.panicCode)
+			}
+			if tt.panicCode == 0 && revert.PanicCode != nil {
+				t.Errorf("PanicCode = %v, want nil", revert.PanicCode)
+			}
+			if revert.ErrorName != tt.errorName {
+				t.Errorf("ErrorName = %q, want %q", revert.ErrorName, tt.errorName)
+			}
+		})
+	}
+}
+
+func TestAsRevertErrorReadsNodeData(t *testing.T) {
+	data := revertData(t, errorSelector, []string{"string"}, "Only owner can call this function")
+	err := &testRPCError{code: rpcCodeExecutionReverted, message: "execution reverted", data: hexutil.Encode(data)}
+
+	revert := asRevertError(err, nil)
+	if revert == nil {
+		t.Fatal("错误码3应解析为 *RevertError")
+	}
+	if revert.Reason != "Only owner can call this function" {
+		t.Errorf("Reason = %q", revert.Reason)
+	}
+	if want := "合约执行被回滚: Only owner can call this function"; revert.Error() != want {
+		t.Errorf("Error() = %q, want %q", revert.Error(), want)
+	}
+	if asRevertError(&testRPCError{code: -32000, message: "nonce too low"}, nil) != nil {
+		t.Error("非回滚错误不应解析为 *RevertError")
+	}
+}
+
+func TestRevertReasonReplaysFailedTransaction(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+	counter := deployCounter(t, client, backend, signer)
+
+	// 显式gas上限跳过估算，计数为0时decrement在链上回滚
+	tx, err := counter.Decrement(ctx, big.NewInt(1), callGas)
+	if err != nil {
+		t.Fatalf("发送decrement失败: %v", err)
+	}
+	backend.Commit()
+
+	revert, err := client.RevertReason(ctx, tx.Hash)
+	if err != nil {
+		t.Fatalf("获取回滚原因失败: %v", err)
+	}
+	if revert.Reason != "Cannot decrement below zero" || !errors.Is(revert, ErrReverted) {
+		t.Errorf("回滚原因 = %q", revert.Reason)
+	}
+	if got := client.FailureReason(ctx, tx.Hash); got != revert.Error() {
+		t.Errorf("FailureReason = %q, want %q", got, revert.Error())
+	}
+
+	// 成功的交易没有回滚原因
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	hash, err := client.SendTransaction(ctx, signer, to.Hex(), big.NewInt(1))
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	backend.Commit()
+	if _, err := client.RevertReason(ctx, hash); err == nil {
+		t.Error("成功的交易不应返回回滚原因")
+	}
+	if got := client.FailureReason(ctx, hash); !strings.HasPrefix(got, "无法获取失败原因") {
+		t.Errorf("FailureReason = %q, want 无法获取失败原因...", got)
+	}
+}
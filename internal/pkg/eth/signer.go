@@ -0,0 +1,122 @@
+package eth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// Signer 交易签名者，屏蔽私钥的具体存放方式
+type Signer interface {
+	// Address 返回签名账户地址
+	Address() common.Address
+	// SignTx 使用指定链ID对交易签名
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// KeystoreSigner 基于Web3 Secret Storage（keystore JSON）的签名者
+type KeystoreSigner struct {
+	key *keystore.Key
+}
+
+// NewKeystoreSigner 使用口令解密keystore JSON并创建签名者
+func NewKeystoreSigner(keyJSON []byte, passphrase string) (*KeystoreSigner, error) {
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("解密keystore失败: %w", err)
+	}
+
+	return &KeystoreSigner{key: key}, nil
+}
+
+// LoadKeystoreSigner 从keystore文件创建签名者
+func LoadKeystoreSigner(path, passphrase string) (*KeystoreSigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取keystore文件失败: %w", err)
+	}
+
+	return NewKeystoreSigner(keyJSON, passphrase)
+}
+
+// Address 返回签名账户地址
+func (s *KeystoreSigner) Address() common.Address {
+	return s.key.Address
+}
+
+// SignTx 使用指定链ID对交易签名
+func (s *KeystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key.PrivateKey)
+}
+
+// PrivateKeySigner 基于明文私钥的签名者，仅用于本地开发和测试
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewPrivateKeySigner 从十六进制私钥创建签名者
+func NewPrivateKeySigner(hexKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("无效的私钥: %w", err)
+	}
+
+	return &PrivateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// Address 返回签名账户地址
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx 使用指定链ID对交易签名
+func (s *PrivateKeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// NewSignerFromConfig 根据账户配置创建签名者
+// 优先使用keystore，只有显式配置insecure_dev_key时才接受明文私钥；
+// 旧版配置中的 test_private_key 不再被接受，避免明文私钥在未显式声明的情况下被使用
+func NewSignerFromConfig(cfg config.AccountsConfig) (Signer, error) {
+	if cfg.LegacyPrivateKey != "" {
+		return nil, fmt.Errorf("不再支持明文私钥 test_private_key: 请使用 cmd/keystore 导入为keystore，本地开发可改用 insecure_dev_key")
+	}
+
+	if cfg.Keystore != "" {
+		passphrase, err := cfg.GetPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		return LoadKeystoreSigner(cfg.Keystore, passphrase)
+	}
+
+	if cfg.InsecureDevKey != "" {
+		return NewPrivateKeySigner(cfg.InsecureDevKey)
+	}
+
+	return nil, fmt.Errorf("未配置签名账户: 请设置 keystore 或 insecure_dev_key")
+}
+
+// TransactOpts 将Signer适配为合约绑定代码使用的TransactOpts
+func TransactOpts(ctx context.Context, signer Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(tx, chainID)
+		},
+		Context: ctx,
+	}
+}
@@ -0,0 +1,117 @@
+package eth
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// writeKeystore 生成随机私钥并以passphrase加密写入临时keystore文件，返回文件路径和私钥
+func writeKeystore(t *testing.T, passphrase string) (string, *keystore.Key) {
+	t.Helper()
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	key := &keystore.Key{Id: uuid.New(), Address: crypto.PubkeyToAddress(privateKey.PublicKey), PrivateKey: privateKey}
+	keyJSON, err := keystore.EncryptKey(key, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("加密私钥失败: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, keyJSON, 0o600); err != nil {
+		t.Fatalf("写入keystore失败: %v", err)
+	}
+	return path, key
+}
+
+func TestKeystoreSignerPassphrase(t *testing.T) {
+	path, key := writeKeystore(t, "correct horse")
+
+	signer, err := LoadKeystoreSigner(path, "correct horse")
+	if err != nil {
+		t.Fatalf("口令正确时解密失败: %v", err)
+	}
+	if signer.Address() != key.Address {
+		t.Errorf("地址 = %s, want %s", signer.Address().Hex(), key.Address.Hex())
+	}
+
+	if _, err := LoadKeystoreSigner(path, "wrong"); err == nil || !strings.Contains(err.Error(), "解密keystore失败") {
+		t.Errorf("口令错误 err = %v, want 解密keystore失败", err)
+	}
+	if _, err := LoadKeystoreSigner(filepath.Join(t.TempDir(), "missing.json"), "correct horse"); err == nil {
+		t.Error("keystore文件不存在时应返回错误")
+	}
+}
+
+func TestNewSignerFromConfig(t *testing.T) {
+	const passphraseEnv = "ETH_SIGNER_TEST_PASSPHRASE"
+	path, key := writeKeystore(t, "s3cret")
+
+	passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatalf("写入口令文件失败: %v", err)
+	}
+	wrongFile := filepath.Join(t.TempDir(), "wrong")
+	if err := os.WriteFile(wrongFile, []byte("wrong"), 0o600); err != nil {
+		t.Fatalf("写入口令文件失败: %v", err)
+	}
+
+	devKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	devHex := hex.EncodeToString(crypto.FromECDSA(devKey))
+	devAddress := crypto.PubkeyToAddress(devKey.PublicKey)
+
+	tests := []struct {
+		name    string
+		env     string // 非空时设置口令环境变量
+		cfg     config.AccountsConfig
+		want    string // 期望的签名地址，为空表示应返回错误
+		wantErr string
+	}{
+		{"口令来自环境变量", "s3cret", config.AccountsConfig{Keystore: path, PassphraseEnv: passphraseEnv}, key.Address.Hex(), ""},
+		{"环境变量优先于口令文件", "s3cret", config.AccountsConfig{Keystore: path, PassphraseEnv: passphraseEnv, PassphraseFile: wrongFile}, key.Address.Hex(), ""},
+		{"口令来自文件并去掉换行", "", config.AccountsConfig{Keystore: path, PassphraseEnv: passphraseEnv, PassphraseFile: passphraseFile}, key.Address.Hex(), ""},
+		{"环境变量中的口令错误", "wrong", config.AccountsConfig{Keystore: path, PassphraseEnv: passphraseEnv}, "", "解密keystore失败"},
+		{"没有口令", "", config.AccountsConfig{Keystore: path, PassphraseEnv: passphraseEnv}, "", "未找到keystore口令"},
+		{"口令文件不存在", "", config.AccountsConfig{Keystore: path, PassphraseFile: filepath.Join(t.TempDir(), "missing")}, "", "读取口令文件失败"},
+		{"keystore优先于明文私钥", "s3cret", config.AccountsConfig{Keystore: path, PassphraseEnv: passphraseEnv, InsecureDevKey: devHex}, key.Address.Hex(), ""},
+		{"显式配置insecure_dev_key", "", config.AccountsConfig{InsecureDevKey: devHex}, devAddress.Hex(), ""},
+		{"拒绝旧版明文私钥", "", config.AccountsConfig{LegacyPrivateKey: devHex}, "", "test_private_key"},
+		{"旧版明文私钥不因insecure_dev_key放行", "", config.AccountsConfig{LegacyPrivateKey: devHex, InsecureDevKey: devHex}, "", "test_private_key"},
+		{"未配置签名账户", "", config.AccountsConfig{}, "", "未配置签名账户"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 空口令也是有效口令，未设置时需要删除环境变量
+			t.Setenv(passphraseEnv, tt.env)
+			if tt.env == "" {
+				os.Unsetenv(passphraseEnv)
+			}
+
+			signer, err := NewSignerFromConfig(tt.cfg)
+			if tt.want == "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want 包含 %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("创建签名者失败: %v", err)
+			}
+			if signer.Address().Hex() != tt.want {
+				t.Errorf("地址 = %s, want %s", signer.Address().Hex(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// testBalance 模拟链上测试账户的初始余额（100 ETH）
+var testBalance = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+
+// newSimulatedClient 创建连接到本地模拟链的客户端以及一个预充值的签名者
+func newSimulatedClient(t *testing.T, network config.NetworkConfig) (*Client, *simulated.Backend, *PrivateKeySigner) {
+	t.Helper()
+	return newSimulatedClientWithAlloc(t, network, nil)
+}
+
+// newSimulatedClientWithAlloc 同 newSimulatedClient，创世区块中额外包含alloc中的账户（如预先部署的合约）
+func newSimulatedClientWithAlloc(t *testing.T, network config.NetworkConfig, alloc types.GenesisAlloc) (*Client, *simulated.Backend, *PrivateKeySigner) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	signer := &PrivateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+
+	genesis := types.GenesisAlloc{signer.address: {Balance: testBalance}}
+	for address, account := range alloc {
+		genesis[address] = account
+	}
+	backend := simulated.NewBackend(genesis)
+	t.Cleanup(func() { backend.Close() })
+
+	client := NewClientFromBackend(backend.Client())
+	client.network = "simulated"
+	client.networkConfig = network
+
+	return client, backend, signer
+}
@@ -0,0 +1,188 @@
+package eth
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// MinResubscribeBackoff 订阅断开或轮询失败后的初始重试间隔
+	MinResubscribeBackoff = time.Second
+	// MaxResubscribeBackoff 重试间隔的上限，每次失败后翻倍直到该值
+	MaxResubscribeBackoff = 30 * time.Second
+	// headBufferSize 区块头通道的缓冲大小
+	headBufferSize = 16
+)
+
+// SubscribeNewHeads 订阅新区块头，从当前最新区块开始按高度依次推送
+// 节点支持订阅（ws/wss、ipc）时使用 eth_subscribe newHeads，否则按网络出块间隔轮询；
+// 断线后按指数退避重连，并补齐断线期间错过的高度，保证推送的区块号连续。
+// 发生重组时可能再次推送已推送过高度的新区块头，消费者可通过ParentHash识别。
+// ctx 取消后通道关闭。
+func (c *Client) SubscribeNewHeads(ctx context.Context) (<-chan *BlockHeader, error) {
+	interval, err := c.blockTime()
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := c.headerByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &headStream{
+		client:   c,
+		interval: interval,
+		heads:    make(chan *BlockHeader, headBufferSize),
+	}
+	go s.run(ctx, head)
+
+	return s.heads, nil
+}
+
+// headStream 一次SubscribeNewHeads调用的推送状态
+type headStream struct {
+	client   *Client
+	interval time.Duration
+	heads    chan *BlockHeader
+	next     uint64 // 下一个待推送的区块号
+}
+
+// run 推送起始区块头后持续跟随链头，直到ctx取消
+func (s *headStream) run(ctx context.Context, head *types.Header) {
+	defer close(s.heads)
+
+	if !s.emit(ctx, head) {
+		return
+	}
+
+	backoff := MinResubscribeBackoff
+	for {
+		// subscribe 和 poll 只在出错时返回
+		start := s.next
+		var err error
+		if s.client.supportsSubscriptions() {
+			err = s.subscribe(ctx)
+		} else {
+			err = s.poll(ctx)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		// 断开前有过进展，说明连接曾经正常，重新从最小间隔开始退避
+		if s.next > start {
+			backoff = MinResubscribeBackoff
+		}
+		log.Printf("⚠️  新区块推送中断，%s后从区块 %d 继续: %v", backoff, s.next, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, MaxResubscribeBackoff)
+	}
+}
+
+// subscribe 通过 eth_subscribe 接收新区块头，订阅断开时返回错误
+func (s *headStream) subscribe(ctx context.Context) error {
+	headers := make(chan *types.Header, headBufferSize)
+	sub, err := s.client.Client.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return wrapError(err, "订阅新区块失败")
+	}
+	defer sub.Unsubscribe()
+
+	// 订阅建立前（包括断线期间）产生的区块先补齐
+	if err := s.catchUp(ctx, nil); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return wrapError(err, "新区块订阅已断开")
+		case header := <-headers:
+			if err := s.catchUp(ctx, new(big.Int).Sub(header.Number, big.NewInt(1))); err != nil {
+				return err
+			}
+			if !s.emit(ctx, header) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// poll 按出块间隔轮询最新区块，查询失败时返回错误
+func (s *headStream) poll(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if err := s.catchUp(ctx, nil); err != nil {
+			return err
+		}
+	}
+}
+
+// catchUp 依次推送 next 到 to（nil表示最新区块）之间尚未推送的区块头
+func (s *headStream) catchUp(ctx context.Context, to *big.Int) error {
+	if to == nil {
+		head, err := s.client.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		to = new(big.Int).SetUint64(head)
+	}
+
+	for number := s.next; to.Sign() >= 0 && number <= to.Uint64(); number++ {
+		header, err := s.client.headerByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		if !s.emit(ctx, header) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// emit 推送区块头并推进next，ctx取消时返回false
+// 收到比已推送高度更低的区块头（重组）时同样推送，并从该高度继续
+func (s *headStream) emit(ctx context.Context, header *types.Header) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case s.heads <- NewBlockHeader(header):
+		s.next = header.Number.Uint64() + 1
+		return true
+	}
+}
+
+// headerByNumber 获取区块头，number为nil时获取最新区块头
+func (c *Client) headerByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	header, err := c.Client.HeaderByNumber(ctx, number)
+	if err != nil {
+		if number == nil {
+			return nil, wrapError(err, "获取最新区块头失败")
+		}
+		return nil, wrapError(err, "获取区块头 %s 失败", number)
+	}
+
+	return header, nil
+}
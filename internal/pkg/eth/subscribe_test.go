@@ -0,0 +1,99 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// droppingBackend 可以主动断开新区块订阅的后端
+type droppingBackend struct {
+	Backend
+	subs chan *droppableSubscription
+}
+
+func (b *droppingBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	sub, err := b.Backend.SubscribeNewHead(ctx, ch)
+	if err != nil {
+		return nil, err
+	}
+	dropped := &droppableSubscription{Subscription: sub, errc: make(chan error, 1)}
+	b.subs <- dropped
+	return dropped, nil
+}
+
+// droppableSubscription 通过drop模拟连接断开的订阅
+type droppableSubscription struct {
+	ethereum.Subscription
+	errc chan error
+}
+
+func (s *droppableSubscription) Err() <-chan error { return s.errc }
+
+func (s *droppableSubscription) drop() {
+	s.errc <- errors.New("websocket: close 1006 (abnormal closure)")
+}
+
+// nextHead 在超时前读取下一个区块头
+func nextHead(t *testing.T, heads <-chan *BlockHeader) *BlockHeader {
+	t.Helper()
+	select {
+	case head, ok := <-heads:
+		if !ok {
+			t.Fatal("区块头通道已关闭")
+		}
+		return head
+	case <-time.After(10 * time.Second):
+		t.Fatal("等待区块头超时")
+		return nil
+	}
+}
+
+func TestSubscribeNewHeadsResubscribesWithoutGaps(t *testing.T) {
+	client, backend, _ := newSimulatedClient(t, config.NetworkConfig{BlockTime: "10ms"})
+	dropping := &droppingBackend{Backend: client.Client, subs: make(chan *droppableSubscription, 4)}
+	client.Client = dropping
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	heads, err := client.SubscribeNewHeads(ctx)
+	if err != nil {
+		t.Fatalf("订阅新区块失败: %v", err)
+	}
+
+	var numbers []uint64
+	numbers = append(numbers, nextHead(t, heads).Number)
+
+	sub := <-dropping.subs
+	backend.Commit()
+	backend.Commit()
+	for len(numbers) < 3 {
+		numbers = append(numbers, nextHead(t, heads).Number)
+	}
+
+	// 断线期间产生的区块在重新订阅后补齐
+	sub.drop()
+	backend.Commit()
+	backend.Commit()
+	backend.Commit()
+	select {
+	case <-dropping.subs:
+	case <-time.After(10 * time.Second):
+		t.Fatal("断开后没有重新订阅")
+	}
+	backend.Commit()
+	for len(numbers) < 7 {
+		numbers = append(numbers, nextHead(t, heads).Number)
+	}
+
+	for i, number := range numbers {
+		if number != uint64(i) {
+			t.Fatalf("推送的区块号 = %v, want 0..6 连续", numbers)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// DefaultStuckBlocks 未配置时判定交易卡住的区块数
+	DefaultStuckBlocks = 3
+	// DefaultFeeBumpPercent 未配置时重新广播的加价百分比
+	DefaultFeeBumpPercent = 15
+	// MinFeeBumpPercent 节点接受同nonce替换交易所要求的最低加价百分比
+	MinFeeBumpPercent = 10
+	// DefaultFinishedRetention 交易完成后仍保留监控记录的时长，期间Wait仍可取得结果
+	DefaultFinishedRetention = 10 * time.Minute
+)
+
+// ErrReplacedExternally 交易的nonce已被非本监控器发出的交易占用
+var ErrReplacedExternally = errors.New("交易已被外部交易替换")
+
+// trackedTx 被监控的交易，包含同一nonce下的全部替换版本
+type trackedTx struct {
+	signer Signer
+	nonce  uint64
+	req    TxRequest     // 最近一次广播的交易请求
+	hashes []common.Hash // 所有广播过的版本，按广播顺序
+	sentAt uint64        // 最近一次广播时的区块高度
+	dated  bool          // sentAt是否有效，广播后查询区块高度失败时为false，由监控循环补记
+
+	done    chan struct{}
+	receipt *types.Receipt
+	err     error
+}
+
+// TxTracker 卡住交易监控器
+// 记录每笔发出的交易，连续若干区块未被打包时以更高费用、相同nonce重新广播；
+// 无论最终哪个替换版本被打包，Wait都返回其收据；完成的交易在 DefaultFinishedRetention 后自动移除
+type TxTracker struct {
+	client       *Client
+	stuckBlocks  uint64
+	bumpPercent  int64
+	pollInterval time.Duration
+	retention    time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu  sync.Mutex
+	txs map[common.Hash]*trackedTx
+}
+
+// NewTxTracker 按客户端所连接网络的tracker配置创建监控器
+func NewTxTracker(client *Client) (*TxTracker, error) {
+	cfg := client.networkConfig.Tracker
+
+	pollInterval, err := client.blockTime()
+	if err != nil {
+		return nil, err
+	}
+
+	stuckBlocks := cfg.StuckBlocks
+	if stuckBlocks == 0 {
+		stuckBlocks = DefaultStuckBlocks
+	}
+
+	bumpPercent := cfg.FeeBumpPercent
+	if bumpPercent == 0 {
+		bumpPercent = DefaultFeeBumpPercent
+	}
+	if bumpPercent < MinFeeBumpPercent {
+		bumpPercent = MinFeeBumpPercent
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TxTracker{
+		client:       client,
+		stuckBlocks:  stuckBlocks,
+		bumpPercent:  bumpPercent,
+		pollInterval: pollInterval,
+		retention:    DefaultFinishedRetention,
+		ctx:          ctx,
+		cancel:       cancel,
+		txs:          make(map[common.Hash]*trackedTx),
+	}, nil
+}
+
+// Close 停止所有监控，尚未完成的Wait将返回错误
+func (t *TxTracker) Close() {
+	t.cancel()
+}
+
+// Send 发送交易并开始监控
+func (t *TxTracker) Send(ctx context.Context, signer Signer, req TxRequest) (*Transaction, error) {
+	result, err := t.client.Send(ctx, signer, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := result.ToRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	// 交易已发出，查询区块高度失败时不能返回错误，由监控循环补记广播高度
+	head, err := t.client.GetLatestBlockNumber(ctx)
+	tracked := &trackedTx{
+		signer: signer,
+		nonce:  *result.Nonce,
+		req:    *sent,
+		hashes: []common.Hash{common.HexToHash(result.Hash)},
+		sentAt: head,
+		dated:  err == nil,
+		done:   make(chan struct{}),
+	}
+
+	t.mu.Lock()
+	t.txs[tracked.hashes[0]] = tracked
+	t.mu.Unlock()
+
+	go t.monitor(tracked)
+	return result, nil
+}
+
+// Wait 等待交易（或其任一替换版本）被打包并返回收据
+func (t *TxTracker) Wait(ctx context.Context, txHash string) (*types.Receipt, error) {
+	tracked, err := t.lookup(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-tracked.done:
+		return tracked.receipt, tracked.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Cancel 以相同nonce发送一笔0值的自转账交易来取消尚未打包的交易
+func (t *TxTracker) Cancel(ctx context.Context, txHash string) (*Transaction, error) {
+	tracked, err := t.lookup(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-tracked.done:
+		return nil, fmt.Errorf("交易 %s 已完成，无法取消", txHash)
+	default:
+	}
+
+	t.mu.Lock()
+	from := tracked.signer.Address()
+	cancelReq := TxRequest{
+		To:        &from,
+		Value:     new(big.Int),
+		GasLimit:  params.TxGas,
+		GasPrice:  tracked.req.GasPrice,
+		GasTipCap: tracked.req.GasTipCap,
+		GasFeeCap: tracked.req.GasFeeCap,
+	}
+	t.mu.Unlock()
+
+	return t.replace(ctx, tracked, cancelReq)
+}
+
+// Forget 立即停止记录交易，不必等待完成后的自动移除
+func (t *TxTracker) Forget(txHash string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked, ok := t.txs[common.HexToHash(txHash)]
+	if !ok {
+		return
+	}
+	t.remove(tracked)
+}
+
+// remove 删除交易全部版本的记录，调用方需持有t.mu
+func (t *TxTracker) remove(tracked *trackedTx) {
+	for _, hash := range tracked.hashes {
+		if t.txs[hash] == tracked {
+			delete(t.txs, hash)
+		}
+	}
+}
+
+// lookup 根据任一版本的交易哈希查找被监控的交易
+func (t *TxTracker) lookup(txHash string) (*trackedTx, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked, ok := t.txs[common.HexToHash(txHash)]
+	if !ok {
+		return nil, fmt.Errorf("交易 %s 未被监控: %w", txHash, ErrNotFound)
+	}
+	return tracked, nil
+}
+
+// monitor 按出块间隔轮询交易状态，直到被打包或监控器关闭
+func (t *TxTracker) monitor(tracked *trackedTx) {
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	externalMisses := 0
+	for {
+		select {
+		case <-t.ctx.Done():
+			t.finish(tracked, nil, t.ctx.Err())
+			return
+		case <-ticker.C:
+		}
+
+		receipt, err := t.findReceipt(tracked)
+		if err != nil {
+			// 节点暂时不可用，下一轮继续
+			continue
+		}
+		if receipt != nil {
+			t.finish(tracked, receipt, nil)
+			return
+		}
+
+		// nonce已被占用却找不到任何版本的收据，连续两轮确认后判定为被外部交易替换
+		nonce, err := t.client.Client.NonceAt(t.ctx, tracked.signer.Address(), nil)
+		if err == nil && nonce > tracked.nonce {
+			externalMisses++
+			if externalMisses >= 2 {
+				t.finish(tracked, nil, ErrReplacedExternally)
+				return
+			}
+			continue
+		}
+		externalMisses = 0
+
+		head, err := t.client.GetLatestBlockNumber(t.ctx)
+		if err != nil {
+			continue
+		}
+
+		t.mu.Lock()
+		if !tracked.dated {
+			tracked.sentAt, tracked.dated = head, true
+		}
+		stuck := head >= tracked.sentAt+t.stuckBlocks
+		req := tracked.req
+		t.mu.Unlock()
+
+		if stuck {
+			// 加价失败（如替换费用仍不足、旧版本恰好被打包）时等待下一轮再处理
+			_, _ = t.replace(t.ctx, tracked, req)
+		}
+	}
+}
+
+// findReceipt 查找交易任一版本的收据，均未打包时返回nil
+func (t *TxTracker) findReceipt(tracked *trackedTx) (*types.Receipt, error) {
+	t.mu.Lock()
+	hashes := append([]common.Hash(nil), tracked.hashes...)
+	t.mu.Unlock()
+
+	for _, hash := range hashes {
+		receipt, err := t.client.Client.TransactionReceipt(t.ctx, hash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// replace 以相同nonce和加价后的费用广播替换交易
+func (t *TxTracker) replace(ctx context.Context, tracked *trackedTx, req TxRequest) (*Transaction, error) {
+	if err := t.bumpFees(ctx, &req); err != nil {
+		return nil, err
+	}
+	nonce := tracked.nonce
+	req.Nonce = &nonce
+	if req.GasLimit == 0 {
+		t.mu.Lock()
+		req.GasLimit = tracked.req.GasLimit
+		t.mu.Unlock()
+	}
+
+	result, err := t.client.Send(ctx, tracked.signer, req)
+	if err != nil {
+		return nil, fmt.Errorf("重新广播交易失败: %w", err)
+	}
+
+	head, err := t.client.GetLatestBlockNumber(ctx)
+
+	hash := common.HexToHash(result.Hash)
+	t.mu.Lock()
+	tracked.req = req
+	tracked.hashes = append(tracked.hashes, hash)
+	tracked.sentAt, tracked.dated = head, err == nil
+	t.txs[hash] = tracked
+	t.mu.Unlock()
+
+	return result, nil
+}
+
+// bumpFees 将请求中的费用按加价百分比提高，且不低于当前建议费用
+func (t *TxTracker) bumpFees(ctx context.Context, req *TxRequest) error {
+	suggested, err := t.client.SuggestFees(ctx)
+	if err != nil {
+		return err
+	}
+
+	if req.GasFeeCap != nil {
+		req.GasFeeCap = maxBig(bumpFee(req.GasFeeCap, t.bumpPercent), suggested.GasFeeCap)
+		req.GasTipCap = maxBig(bumpFee(req.GasTipCap, t.bumpPercent), suggested.GasTipCap)
+		req.GasPrice = nil
+		return nil
+	}
+
+	req.GasPrice = maxBig(bumpFee(req.GasPrice, t.bumpPercent), suggested.GasPrice)
+	return nil
+}
+
+// finish 记录监控结果并唤醒所有等待者，保留期过后删除记录
+func (t *TxTracker) finish(tracked *trackedTx, receipt *types.Receipt, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	select {
+	case <-tracked.done:
+		return
+	default:
+	}
+	tracked.receipt = receipt
+	tracked.err = err
+	close(tracked.done)
+
+	time.AfterFunc(t.retention, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.remove(tracked)
+	})
+}
+
+// bumpFee 按百分比向上取整地提高费用
+func bumpFee(fee *big.Int, percent int64) *big.Int {
+	if fee == nil {
+		return nil
+	}
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// maxBig 返回两者中较大的值，忽略nil
+func maxBig(a, b *big.Int) *big.Int {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,197 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"go-eth-backend/internal/pkg/config"
+)
+
+// stuckFees 远低于模拟链baseFee的费用，交易能进入交易池但不会被打包
+var stuckFees = TxRequest{GasLimit: params.TxGas, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1)}
+
+// newTestTracker 创建轮询间隔很短的监控器，并在后台持续出块
+func newTestTracker(t *testing.T, stuckBlocks uint64) (*TxTracker, *Client, *PrivateKeySigner) {
+	t.Helper()
+
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{
+		BlockTime: "10ms",
+		Tracker:   config.TrackerConfig{StuckBlocks: stuckBlocks},
+	})
+	tracker, err := NewTxTracker(client)
+	if err != nil {
+		t.Fatalf("创建监控器失败: %v", err)
+	}
+	tracker.retention = 50 * time.Millisecond
+	t.Cleanup(tracker.Close)
+
+	mine(t, backend, 10*time.Millisecond)
+	return tracker, client, signer
+}
+
+// mine 在后台按固定间隔出块直到测试结束
+func mine(t *testing.T, backend *simulated.Backend, interval time.Duration) {
+	t.Helper()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				backend.Commit()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+	})
+}
+
+func waitTracked(t *testing.T, tracker *TxTracker, hash string) (*types.Receipt, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return tracker.Wait(ctx, hash)
+}
+
+func TestTxTrackerBumpsStuckTransaction(t *testing.T) {
+	tracker, _, signer := newTestTracker(t, 1)
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	req := stuckFees
+	req.To = &to
+	req.Value = big.NewInt(1)
+	sent, err := tracker.Send(context.Background(), signer, req)
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+
+	receipt, err := waitTracked(t, tracker, sent.Hash)
+	if err != nil {
+		t.Fatalf("等待交易失败: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("交易执行失败, status = %d", receipt.Status)
+	}
+	if receipt.TxHash.Hex() == sent.Hash {
+		t.Fatal("卡住的交易应被加价后的替换版本取代")
+	}
+
+	// 完成的交易在保留期过后被移除
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := tracker.lookup(sent.Hash); errors.Is(err, ErrNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("完成的交易没有被移除")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := tracker.lookup(receipt.TxHash.Hex()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("替换版本也应被移除, err = %v", err)
+	}
+}
+
+func TestTxTrackerCancel(t *testing.T) {
+	tracker, client, signer := newTestTracker(t, 1000)
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	req := stuckFees
+	req.To = &to
+	req.Value = big.NewInt(1e15)
+	sent, err := tracker.Send(context.Background(), signer, req)
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+
+	cancelTx, err := tracker.Cancel(context.Background(), sent.Hash)
+	if err != nil {
+		t.Fatalf("取消交易失败: %v", err)
+	}
+	if cancelTx.To != signer.Address().Hex() || cancelTx.Value.Sign() != 0 || *cancelTx.Nonce != *sent.Nonce {
+		t.Errorf("取消交易应为相同nonce的0值自转账, got %+v", cancelTx)
+	}
+
+	receipt, err := waitTracked(t, tracker, sent.Hash)
+	if err != nil {
+		t.Fatalf("等待交易失败: %v", err)
+	}
+	if receipt.TxHash.Hex() != cancelTx.Hash {
+		t.Errorf("被打包的交易 = %s, want 取消交易 %s", receipt.TxHash.Hex(), cancelTx.Hash)
+	}
+
+	balance, err := client.GetBalance(context.Background(), to.Hex())
+	if err != nil {
+		t.Fatalf("获取余额失败: %v", err)
+	}
+	if balance.Sign() != 0 {
+		t.Errorf("原交易已取消，接收方余额 = %s, want 0", balance)
+	}
+
+	if _, err := tracker.Cancel(context.Background(), sent.Hash); err == nil {
+		t.Error("已完成的交易不能再取消")
+	}
+}
+
+func TestTxTrackerReplacedExternally(t *testing.T) {
+	tracker, client, signer := newTestTracker(t, 1000)
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	req := stuckFees
+	req.To = &to
+	sent, err := tracker.Send(context.Background(), signer, req)
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+
+	// 绕过监控器，以相同nonce和正常费用发送另一笔交易
+	external := TxRequest{
+		To:        &to,
+		Nonce:     sent.Nonce,
+		GasLimit:  params.TxGas,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: big.NewInt(2 * params.GWei),
+	}
+	if _, err := client.Send(context.Background(), signer, external); err != nil {
+		t.Fatalf("发送外部交易失败: %v", err)
+	}
+
+	if _, err := waitTracked(t, tracker, sent.Hash); !errors.Is(err, ErrReplacedExternally) {
+		t.Fatalf("err = %v, want ErrReplacedExternally", err)
+	}
+}
+
+func TestBumpFee(t *testing.T) {
+	tests := []struct {
+		fee     int64
+		percent int64
+		want    int64
+	}{
+		{100, 10, 110},
+		{101, 10, 112}, // 向上取整
+		{1, 15, 2},
+	}
+	for _, tt := range tests {
+		if got := bumpFee(big.NewInt(tt.fee), tt.percent); got.Int64() != tt.want {
+			t.Errorf("bumpFee(%d, %d) = %s, want %d", tt.fee, tt.percent, got, tt.want)
+		}
+	}
+	if bumpFee(nil, 10) != nil {
+		t.Error("bumpFee(nil) 应返回nil")
+	}
+}
@@ -2,108 +2,432 @@ package eth
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
-	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// 交易类型名称，对应EIP-2718交易类型
+const (
+	TxTypeLegacy     = "legacy"     // 0x0
+	TxTypeAccessList = "accessList" // 0x1 EIP-2930
+	TxTypeDynamicFee = "dynamicFee" // 0x2 EIP-1559
+	TxTypeBlob       = "blob"       // 0x3 EIP-4844
 )
 
 // Transaction 交易信息结构体
+// 既是交易请求的JSON形式（未填写的字段由Client.Send自动补齐），也是发送结果和查询结果的JSON形式
+// Type、区块相关字段和EffectiveGasPrice只在查询结果中填写，作为请求时被忽略
 type Transaction struct {
-	Hash        string   `json:"hash"`
-	From        string   `json:"from"`
-	To          string   `json:"to"`
-	Value       *big.Int `json:"value"`
-	GasPrice    *big.Int `json:"gasPrice"`
-	GasLimit    uint64   `json:"gasLimit"`
-	Nonce       uint64   `json:"nonce"`
-	Data        []byte   `json:"data"`
-	ChainID     *big.Int `json:"chainId"`
-}
-
-// SendTransaction 发送以太币交易
-func (c *Client) SendTransaction(fromPrivateKey, toAddress string, amount *big.Int) (string, error) {
-	ctx := context.Background()
-	
-	// 解析私钥
-	privateKey, err := crypto.HexToECDSA(fromPrivateKey)
+	Hash                 string           `json:"hash,omitempty"`
+	Type                 string           `json:"type,omitempty"`
+	From                 string           `json:"from,omitempty"`
+	To                   string           `json:"to,omitempty"`
+	Value                *big.Int         `json:"value"`
+	GasPrice             *big.Int         `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *big.Int         `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *big.Int         `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerBlobGas     *big.Int         `json:"maxFeePerBlobGas,omitempty"`
+	BlobHashes           []common.Hash    `json:"blobVersionedHashes,omitempty"`
+	EffectiveGasPrice    *big.Int         `json:"effectiveGasPrice,omitempty"`
+	GasLimit             uint64           `json:"gasLimit,omitempty"`
+	Nonce                *uint64          `json:"nonce,omitempty"`
+	Data                 hexutil.Bytes    `json:"data,omitempty"`
+	AccessList           types.AccessList `json:"accessList,omitempty"`
+	ChainID              *big.Int         `json:"chainId,omitempty"`
+	ContractAddress      string           `json:"contractAddress,omitempty"`
+	BlockHash            string           `json:"blockHash,omitempty"`
+	BlockNumber          *uint64          `json:"blockNumber,omitempty"`
+	TransactionIndex     *uint64          `json:"transactionIndex,omitempty"`
+	Pending              bool             `json:"pending"`
+}
+
+// ParseTransaction 从原生交易类型解析为Transaction结构
+// 发送方按交易类型选择签名器恢复；交易不在区块上下文中时区块相关字段和EffectiveGasPrice为空
+func ParseTransaction(tx *types.Transaction, isPending bool) *Transaction {
+	nonce := tx.Nonce()
+	result := &Transaction{
+		Hash:       tx.Hash().Hex(),
+		Type:       txTypeName(tx.Type()),
+		Value:      tx.Value(),
+		GasPrice:   tx.GasPrice(),
+		GasLimit:   tx.Gas(),
+		Nonce:      &nonce,
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+		ChainID:    tx.ChainId(),
+		Pending:    isPending,
+	}
+
+	switch tx.Type() {
+	case types.BlobTxType:
+		result.MaxFeePerBlobGas = tx.BlobGasFeeCap()
+		result.BlobHashes = tx.BlobHashes()
+		fallthrough
+	case types.DynamicFeeTxType:
+		result.MaxFeePerGas = tx.GasFeeCap()
+		result.MaxPriorityFeePerGas = tx.GasTipCap()
+	}
+
+	if tx.To() != nil {
+		result.To = tx.To().Hex()
+	}
+
+	from, err := types.Sender(txSigner(tx), tx)
+	if err == nil {
+		result.From = from.Hex()
+		if tx.To() == nil {
+			result.ContractAddress = crypto.CreateAddress(from, nonce).Hex()
+		}
+	}
+
+	return result
+}
+
+// ParseBlockTransaction 解析区块中序号为index的交易，并填写所在区块和实际支付的gas价格
+func ParseBlockTransaction(tx *types.Transaction, header *types.Header, index int) *Transaction {
+	result := ParseTransaction(tx, false)
+
+	number := header.Number.Uint64()
+	txIndex := uint64(index)
+	result.BlockHash = header.Hash().Hex()
+	result.BlockNumber = &number
+	result.TransactionIndex = &txIndex
+	result.EffectiveGasPrice = effectiveGasPrice(tx, header.BaseFee)
+
+	return result
+}
+
+// Receipt 交易收据信息结构体，用于API的JSON输出
+type Receipt struct {
+	TransactionHash   string   `json:"transactionHash"`
+	TransactionIndex  uint     `json:"transactionIndex"`
+	BlockHash         string   `json:"blockHash"`
+	BlockNumber       uint64   `json:"blockNumber"`
+	Type              string   `json:"type"`
+	From              string   `json:"from,omitempty"`
+	To                string   `json:"to,omitempty"`
+	Status            uint64   `json:"status"`
+	GasUsed           uint64   `json:"gasUsed"`
+	CumulativeGasUsed uint64   `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *big.Int `json:"effectiveGasPrice"`
+	BlobGasUsed       uint64   `json:"blobGasUsed,omitempty"`
+	BlobGasPrice      *big.Int `json:"blobGasPrice,omitempty"`
+	ContractAddress   string   `json:"contractAddress,omitempty"`
+	Logs              []*Event `json:"logs"`
+}
+
+// ParseReceipt 从原生收据类型解析为Receipt结构
+// 收据本身不含发送方和接收方，从对应的交易恢复；tx为nil时这两个字段为空
+func ParseReceipt(receipt *types.Receipt, tx *types.Transaction) *Receipt {
+	result := &Receipt{
+		TransactionHash:   receipt.TxHash.Hex(),
+		TransactionIndex:  receipt.TransactionIndex,
+		BlockHash:         receipt.BlockHash.Hex(),
+		Type:              txTypeName(receipt.Type),
+		Status:            receipt.Status,
+		GasUsed:           receipt.GasUsed,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		EffectiveGasPrice: receipt.EffectiveGasPrice,
+		BlobGasUsed:       receipt.BlobGasUsed,
+		BlobGasPrice:      receipt.BlobGasPrice,
+		Logs:              make([]*Event, len(receipt.Logs)),
+	}
+
+	if receipt.BlockNumber != nil {
+		result.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		result.ContractAddress = receipt.ContractAddress.Hex()
+	}
+	for i, log := range receipt.Logs {
+		result.Logs[i] = NewEvent(*log)
+	}
+
+	if tx != nil {
+		if from, err := types.Sender(txSigner(tx), tx); err == nil {
+			result.From = from.Hex()
+		}
+		if tx.To() != nil {
+			result.To = tx.To().Hex()
+		}
+	}
+
+	return result
+}
+
+// txSigner 返回能恢复交易发送方的签名器
+// 未受EIP-155保护的legacy交易不含链ID，使用Homestead签名器，其余交易使用交易自身的链ID
+func txSigner(tx *types.Transaction) types.Signer {
+	if tx.Type() == types.LegacyTxType && !tx.Protected() {
+		return types.HomesteadSigner{}
+	}
+	return types.LatestSignerForChainID(tx.ChainId())
+}
+
+// effectiveGasPrice 计算交易在给定baseFee的区块中实际支付的gas价格，baseFee为nil（伦敦升级前）时为gasPrice
+func effectiveGasPrice(tx *types.Transaction, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return tx.GasPrice()
+	}
+	tip, err := tx.EffectiveGasTip(baseFee)
 	if err != nil {
-		return "", fmt.Errorf("invalid private key: %v", err)
+		// 费用上限低于baseFee的交易不会被打包，这里只可能是数据异常
+		return tx.GasFeeCap()
+	}
+	return tip.Add(tip, baseFee)
+}
+
+// txTypeName 返回交易类型名称，未知类型返回十六进制类型号
+func txTypeName(txType uint8) string {
+	switch txType {
+	case types.LegacyTxType:
+		return TxTypeLegacy
+	case types.AccessListTxType:
+		return TxTypeAccessList
+	case types.DynamicFeeTxType:
+		return TxTypeDynamicFee
+	case types.BlobTxType:
+		return TxTypeBlob
+	default:
+		return hexutil.EncodeUint64(uint64(txType))
 	}
+}
 
-	// 获取公钥地址
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return "", fmt.Errorf("error casting public key to ECDSA")
+// ToRequest 将JSON形式的交易转换为交易请求，to为空表示合约创建
+func (t *Transaction) ToRequest() (*TxRequest, error) {
+	req := &TxRequest{
+		Value:      t.Value,
+		Data:       t.Data,
+		GasLimit:   t.GasLimit,
+		GasPrice:   t.GasPrice,
+		GasTipCap:  t.MaxPriorityFeePerGas,
+		GasFeeCap:  t.MaxFeePerGas,
+		Nonce:      t.Nonce,
+		AccessList: t.AccessList,
 	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
 
-	// 获取nonce
-	nonce, err := c.Client.PendingNonceAt(ctx, fromAddress)
+	if t.To != "" {
+		if !common.IsHexAddress(t.To) {
+			return nil, fmt.Errorf("无效的接收方地址: %s", t.To)
+		}
+		to := common.HexToAddress(t.To)
+		req.To = &to
+	}
+
+	return req, nil
+}
+
+// TxRequest 交易请求
+// 未设置的字段由Client.Send自动补齐：GasLimit为0时估算gas，Nonce为nil时读取待处理nonce，
+// 费用字段均为空时按网络费用配置获取，只指定 GasTipCap 或 GasFeeCap 时补齐另一项；To为nil表示合约创建
+type TxRequest struct {
+	To         *common.Address
+	Value      *big.Int
+	Data       []byte
+	GasLimit   uint64
+	GasPrice   *big.Int
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Nonce      *uint64
+	AccessList types.AccessList
+}
+
+// Send 补齐交易请求中缺失的字段，签名并发送交易
+// 未指定nonce时由客户端的NonceManager分配，节点返回nonce过低时重新同步并重试一次；
+// 签名失败或节点明确拒绝时归还nonce，其他发送错误无法确定交易是否已到达节点，丢弃本地nonce状态
+func (c *Client) Send(ctx context.Context, signer Signer, req TxRequest) (*Transaction, error) {
+	ctx, cancel := c.withTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	from := signer.Address()
+
+	chainID, err := c.Client.ChainID(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to get nonce: %v", err)
+		return nil, wrapError(err, "获取链ID失败")
 	}
 
-	// 获取gas价格
-gasPrice, err := c.Client.SuggestGasPrice(ctx)
+	// 获取交易费用
+	fees, err := c.requestFees(ctx, &req)
 	if err != nil {
-		return "", fmt.Errorf("failed to get gas price: %v", err)
+		return nil, err
 	}
 
-	// 获取链ID
-	chainID, err := c.Client.ChainID(ctx)
+	// 估算gas
+	gasLimit := req.GasLimit
+	if gasLimit == 0 {
+		gasLimit, err = c.estimateGas(ctx, from, &req, fees)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 调用方指定nonce时不经过NonceManager
+	if req.Nonce != nil {
+		signedTx, err := c.signAndSend(ctx, signer, chainID, *req.Nonce, &req, gasLimit, fees)
+		if err != nil {
+			return nil, err
+		}
+		return ParseTransaction(signedTx, true), nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		nonce, err := c.nonces.Acquire(ctx, chainID, from)
+		if err != nil {
+			return nil, err
+		}
+
+		signedTx, err := signTx(signer, chainID, nonce, &req, gasLimit, fees)
+		if err != nil {
+			// 交易没有发出，nonce可以复用
+			c.nonces.Release(chainID, from, nonce)
+			return nil, err
+		}
+
+		err = c.sendTx(ctx, signedTx)
+		switch {
+		case err == nil:
+			return ParseTransaction(signedTx, true), nil
+		case errors.Is(err, ErrNonceTooLow):
+			c.nonces.Reset(chainID, from)
+			if attempt > 0 {
+				return nil, err
+			}
+		case isRejected(err):
+			// 节点明确拒绝，交易没有进入交易池，nonce可以复用
+			c.nonces.Release(chainID, from, nonce)
+			return nil, err
+		default:
+			// 超时、连接中断等情况下交易可能已被节点接收，复用nonce会与其冲突，下次分配时从节点重新同步
+			c.nonces.Reset(chainID, from)
+			return nil, err
+		}
+	}
+}
+
+// signAndSend 构建、签名并发送交易
+func (c *Client) signAndSend(ctx context.Context, signer Signer, chainID *big.Int, nonce uint64, req *TxRequest, gasLimit uint64, fees *FeeParams) (*types.Transaction, error) {
+	signedTx, err := signTx(signer, chainID, nonce, req, gasLimit, fees)
 	if err != nil {
-		return "", fmt.Errorf("failed to get chain ID: %v", err)
+		return nil, err
 	}
+	if err := c.sendTx(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
 
-	// 构建交易
-	tx := types.NewTransaction(
-		nonce,
-		common.HexToAddress(toAddress),
-		amount,
-		21000, // 标准的以太币转账gas限制
-		gasPrice,
-		nil,
-	)
+// signTx 构建并签名交易
+func signTx(signer Signer, chainID *big.Int, nonce uint64, req *TxRequest, gasLimit uint64, fees *FeeParams) (*types.Transaction, error) {
+	tx := newTransaction(chainID, nonce, req.To, req.Value, gasLimit, req.Data, req.AccessList, fees)
 
-	// 签名交易
-	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), privateKey)
+	signedTx, err := signer.SignTx(tx, chainID)
 	if err != nil {
-		return "", fmt.Errorf("failed to sign transaction: %v", err)
+		return nil, fmt.Errorf("签名交易失败: %w", err)
+	}
+	return signedTx, nil
+}
+
+// sendTx 发送已签名的交易
+func (c *Client) sendTx(ctx context.Context, signedTx *types.Transaction) error {
+	if err := c.Client.SendTransaction(ctx, signedTx); err != nil {
+		return wrapError(err, "发送交易失败")
+	}
+	return nil
+}
+
+// requestFees 使用请求中指定的费用，未指定时按网络费用配置获取
+// 只指定 GasFeeCap 时小费取建议值且不超过 GasFeeCap；只指定 GasTipCap 时按最新baseFee计算 GasFeeCap
+func (c *Client) requestFees(ctx context.Context, req *TxRequest) (*FeeParams, error) {
+	switch {
+	case req.GasFeeCap != nil && req.GasTipCap != nil:
+		if req.GasTipCap.Cmp(req.GasFeeCap) > 0 {
+			return nil, fmt.Errorf("小费 %s 超过最高费用 %s", req.GasTipCap, req.GasFeeCap)
+		}
+		return &FeeParams{GasTipCap: req.GasTipCap, GasFeeCap: req.GasFeeCap}, nil
+	case req.GasFeeCap != nil:
+		tipCap, err := c.Client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, wrapError(err, "获取建议小费失败")
+		}
+		if tipCap.Cmp(req.GasFeeCap) > 0 {
+			tipCap = new(big.Int).Set(req.GasFeeCap)
+		}
+		return &FeeParams{GasTipCap: tipCap, GasFeeCap: req.GasFeeCap}, nil
+	case req.GasTipCap != nil:
+		baseFee, err := c.latestBaseFee(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &FeeParams{
+			GasTipCap: req.GasTipCap,
+			GasFeeCap: maxFeePerGas(baseFee, req.GasTipCap, c.feeConfig().MaxFeeMultiplier),
+		}, nil
+	case req.GasPrice != nil:
+		return &FeeParams{GasPrice: req.GasPrice}, nil
+	default:
+		return c.SuggestFees(ctx)
+	}
+}
+
+// estimateGas 估算交易gas并附加安全余量；纯转账的21000 gas无需余量
+func (c *Client) estimateGas(ctx context.Context, from common.Address, req *TxRequest, fees *FeeParams) (uint64, error) {
+	msg := ethereum.CallMsg{
+		From:       from,
+		To:         req.To,
+		Value:      req.Value,
+		Data:       req.Data,
+		AccessList: req.AccessList,
+		GasPrice:   fees.GasPrice,
+		GasTipCap:  fees.GasTipCap,
+		GasFeeCap:  fees.GasFeeCap,
 	}
 
-	// 发送交易
-	err = c.Client.SendTransaction(ctx, signedTx)
+	gas, err := c.Client.EstimateGas(ctx, msg)
 	if err != nil {
-		return "", fmt.Errorf("failed to send transaction: %v", err)
+		return 0, c.wrapCallError(err, "估算gas失败")
+	}
+	if gas == params.TxGas && len(req.Data) == 0 {
+		return gas, nil
 	}
 
-	return signedTx.Hash().Hex(), nil
+	return uint64(float64(gas) * c.feeConfig().GasLimitMultiplier), nil
 }
 
+// SendTransaction 发送以太币交易，返回交易哈希
+func (c *Client) SendTransaction(ctx context.Context, signer Signer, toAddress string, amount *big.Int) (string, error) {
+	to := common.HexToAddress(toAddress)
+	tx, err := c.Send(ctx, signer, TxRequest{To: &to, Value: amount})
+	if err != nil {
+		return "", err
+	}
 
+	return tx.Hash, nil
+}
 
 // WaitForTransactionReceipt 等待交易确认
-func (c *Client) WaitForTransactionReceipt(txHash string) (*types.Receipt, error) {
+func (c *Client) WaitForTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
 	// 设置超时上下文
-	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
+	ctx, cancel := c.withTimeout(ctx, c.receiptTimeout)
 	defer cancel()
 	
 	// 等待交易确认
-	tx, _, err := c.GetTransactionByHash(txHash)
+	tx, _, err := c.GetTransactionByHash(ctx, txHash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction: %v", err)
+		return nil, wrapError(err, "获取交易 %s 失败", txHash)
 	}
 	
 	receipt, err := bind.WaitMined(ctx, c.Client, tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for transaction receipt: %v", err)
+		return nil, wrapError(err, "等待交易 %s 确认失败", txHash)
 	}
 	
 	return receipt, nil
@@ -0,0 +1,378 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"go-eth-backend/internal/pkg/config"
+)
+
+func TestTransactionToRequest(t *testing.T) {
+	nonce := uint64(7)
+	tx := &Transaction{
+		To:                   "0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20",
+		Value:                big.NewInt(100),
+		GasLimit:             50000,
+		MaxFeePerGas:         big.NewInt(30),
+		MaxPriorityFeePerGas: big.NewInt(2),
+		Nonce:                &nonce,
+		Data:                 []byte{0x01, 0x02},
+	}
+
+	req, err := tx.ToRequest()
+	if err != nil {
+		t.Fatalf("转换交易请求失败: %v", err)
+	}
+	if req.To == nil || *req.To != common.HexToAddress(tx.To) {
+		t.Errorf("To = %v, want %s", req.To, tx.To)
+	}
+	if req.Value.Cmp(tx.Value) != 0 || req.GasLimit != tx.GasLimit || *req.Nonce != nonce {
+		t.Errorf("请求字段与交易不一致: %+v", req)
+	}
+	if req.GasFeeCap.Cmp(big.NewInt(30)) != 0 || req.GasTipCap.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("费用 = %s/%s, want 30/2", req.GasFeeCap, req.GasTipCap)
+	}
+	if string(req.Data) != string(tx.Data) {
+		t.Errorf("Data = %x, want %x", req.Data, tx.Data)
+	}
+
+	creation, err := (&Transaction{Data: []byte{0x60}}).ToRequest()
+	if err != nil {
+		t.Fatalf("转换合约创建请求失败: %v", err)
+	}
+	if creation.To != nil {
+		t.Errorf("to为空时应为合约创建, To = %v", creation.To)
+	}
+
+	if _, err := (&Transaction{To: "0x1234"}).ToRequest(); err == nil {
+		t.Error("无效的接收方地址应返回错误")
+	}
+}
+
+func TestRequestFees(t *testing.T) {
+	ctx := context.Background()
+	client, _, _ := newSimulatedClient(t, config.NetworkConfig{
+		Fees: config.FeeConfig{MaxFeeMultiplier: 2},
+	})
+
+	head, err := client.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatalf("获取区块头失败: %v", err)
+	}
+	suggested, err := client.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		t.Fatalf("获取小费失败: %v", err)
+	}
+
+	// 只指定 GasFeeCap 且低于建议小费时，小费不超过 GasFeeCap
+	feeCap := new(big.Int).Sub(suggested, big.NewInt(1))
+	fees, err := client.requestFees(ctx, &TxRequest{GasFeeCap: feeCap})
+	if err != nil {
+		t.Fatalf("补齐费用失败: %v", err)
+	}
+	if fees.GasTipCap.Cmp(feeCap) != 0 || fees.GasFeeCap.Cmp(feeCap) != 0 {
+		t.Errorf("费用 = %s/%s, want 小费截断为 %s", fees.GasTipCap, fees.GasFeeCap, feeCap)
+	}
+
+	// 只指定 GasFeeCap 且足够高时使用建议小费
+	highCap := new(big.Int).Mul(suggested, big.NewInt(10))
+	fees, err = client.requestFees(ctx, &TxRequest{GasFeeCap: highCap})
+	if err != nil {
+		t.Fatalf("补齐费用失败: %v", err)
+	}
+	if fees.GasTipCap.Cmp(suggested) != 0 {
+		t.Errorf("GasTipCap = %s, want %s", fees.GasTipCap, suggested)
+	}
+
+	// 只指定 GasTipCap 时按baseFee计算 GasFeeCap
+	tip := big.NewInt(3)
+	fees, err = client.requestFees(ctx, &TxRequest{GasTipCap: tip})
+	if err != nil {
+		t.Fatalf("补齐费用失败: %v", err)
+	}
+	if want := maxFeePerGas(head.BaseFee, tip, 2); fees.GasTipCap.Cmp(tip) != 0 || fees.GasFeeCap.Cmp(want) != 0 {
+		t.Errorf("费用 = %s/%s, want %s/%s", fees.GasTipCap, fees.GasFeeCap, tip, want)
+	}
+
+	// 同时指定且小费超过最高费用时返回错误
+	if _, err := client.requestFees(ctx, &TxRequest{GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(5)}); err == nil {
+		t.Error("小费超过最高费用应返回错误")
+	}
+
+	fees, err = client.requestFees(ctx, &TxRequest{GasPrice: big.NewInt(9)})
+	if err != nil {
+		t.Fatalf("补齐费用失败: %v", err)
+	}
+	if fees.IsDynamic() || fees.GasPrice.Cmp(big.NewInt(9)) != 0 {
+		t.Errorf("指定GasPrice时应为legacy费用, got %+v", fees)
+	}
+}
+
+func TestSendWithTipCapOnly(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	tip := big.NewInt(2)
+	sent, err := client.Send(ctx, signer, TxRequest{To: &to, Value: big.NewInt(1), GasTipCap: tip})
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	backend.Commit()
+
+	tx, _, err := client.GetTransactionByHash(ctx, sent.Hash)
+	if err != nil {
+		t.Fatalf("获取交易失败: %v", err)
+	}
+	if tx.Type() != types.DynamicFeeTxType || tx.GasTipCap().Cmp(tip) != 0 {
+		t.Errorf("交易类型 = %d, GasTipCap = %s, want 动态费用交易, 小费 %s", tx.Type(), tx.GasTipCap(), tip)
+	}
+	if tx.GasFeeCap().Cmp(tip) <= 0 {
+		t.Errorf("GasFeeCap = %s, 应包含baseFee", tx.GasFeeCap())
+	}
+}
+
+func TestSendContractCreation(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+
+	// 最小的创建代码：返回一个字节的运行时代码 0x00
+	initCode := common.FromHex("0x600160006000396001600af3")
+	sent, err := client.Send(ctx, signer, TxRequest{Data: initCode, GasLimit: 100000})
+	if err != nil {
+		t.Fatalf("发送合约创建交易失败: %v", err)
+	}
+	backend.Commit()
+
+	if want := crypto.CreateAddress(signer.Address(), 0).Hex(); sent.ContractAddress != want {
+		t.Errorf("ContractAddress = %s, want %s", sent.ContractAddress, want)
+	}
+	if sent.To != "" {
+		t.Errorf("合约创建交易的To = %s, want 空", sent.To)
+	}
+
+	receipt, err := client.GetTransactionReceipt(ctx, sent.Hash)
+	if err != nil {
+		t.Fatalf("获取收据失败: %v", err)
+	}
+	if receipt.ContractAddress.Hex() != sent.ContractAddress {
+		t.Errorf("收据合约地址 = %s, want %s", receipt.ContractAddress.Hex(), sent.ContractAddress)
+	}
+}
+
+func TestSendExplicitNonce(t *testing.T) {
+	ctx := context.Background()
+	client, backend, signer := newSimulatedClient(t, config.NetworkConfig{})
+
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	nonce := uint64(0)
+	sent, err := client.Send(ctx, signer, TxRequest{To: &to, Nonce: &nonce})
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	if *sent.Nonce != 0 {
+		t.Errorf("nonce = %d, want 0", *sent.Nonce)
+	}
+	backend.Commit()
+
+	// 显式nonce不经过NonceManager，重复使用已确认的nonce应被节点拒绝
+	if _, err := client.Send(ctx, signer, TxRequest{To: &to, Nonce: &nonce}); err == nil {
+		t.Error("重复使用已确认的nonce应返回错误")
+	}
+
+	// 之后自动分配的nonce从节点同步
+	next, err := client.Send(ctx, signer, TxRequest{To: &to})
+	if err != nil {
+		t.Fatalf("发送交易失败: %v", err)
+	}
+	if *next.Nonce != 1 {
+		t.Errorf("自动分配的nonce = %d, want 1", *next.Nonce)
+	}
+}
+
+// parseTestTx 一种交易类型的测试数据
+type parseTestTx struct {
+	name     string
+	typeName string
+	tx       types.TxData
+	signer   types.Signer
+	// protected 交易是否受EIP-155保护，即签名中是否包含链ID
+	protected bool
+}
+
+func parseTestTxs() []parseTestTx {
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+	chainID := big.NewInt(1337)
+	return []parseTestTx{
+		{
+			name:     "legacy未受保护",
+			typeName: TxTypeLegacy,
+			tx:       &types.LegacyTx{Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(10)},
+			signer:   types.HomesteadSigner{},
+		},
+		{
+			name:      "legacy EIP-155",
+			typeName:  TxTypeLegacy,
+			tx:        &types.LegacyTx{Nonce: 2, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(10)},
+			signer:    types.NewEIP155Signer(chainID),
+			protected: true,
+		},
+		{
+			name:     "EIP-2930",
+			typeName: TxTypeAccessList,
+			tx: &types.AccessListTx{ChainID: chainID, Nonce: 3, To: &to, Value: big.NewInt(1), Gas: 30000, GasPrice: big.NewInt(10),
+				AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}}},
+			signer:    types.NewEIP2930Signer(chainID),
+			protected: true,
+		},
+		{
+			name:      "EIP-1559",
+			typeName:  TxTypeDynamicFee,
+			tx:        &types.DynamicFeeTx{ChainID: chainID, Nonce: 4, To: &to, Value: big.NewInt(1), Gas: 21000, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(30)},
+			signer:    types.NewLondonSigner(chainID),
+			protected: true,
+		},
+		{
+			name:     "EIP-4844",
+			typeName: TxTypeBlob,
+			tx: &types.BlobTx{ChainID: uint256.MustFromBig(chainID), Nonce: 5, To: to, Value: uint256.NewInt(1), Gas: 21000,
+				GasTipCap: uint256.NewInt(2), GasFeeCap: uint256.NewInt(30), BlobFeeCap: uint256.NewInt(7),
+				BlobHashes: []common.Hash{{0x01, 0x02}}},
+			signer:    types.NewCancunSigner(chainID),
+			protected: true,
+		},
+	}
+}
+
+func TestParseTransactionTypes(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	for _, tt := range parseTestTxs() {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := types.SignNewTx(key, tt.signer, tt.tx)
+			if err != nil {
+				t.Fatalf("签名失败: %v", err)
+			}
+			if tx.Protected() != tt.protected {
+				t.Fatalf("Protected() = %v, want %v", tx.Protected(), tt.protected)
+			}
+			// 未受保护的交易只能用Homestead签名器恢复发送方
+			if _, ok := txSigner(tx).(types.HomesteadSigner); ok == tt.protected {
+				t.Errorf("txSigner() = %T", txSigner(tx))
+			}
+
+			parsed := ParseTransaction(tx, true)
+			if parsed.Type != tt.typeName || parsed.From != from.Hex() || parsed.Hash != tx.Hash().Hex() {
+				t.Errorf("类型/发送方/哈希 = %s/%s/%s, want %s/%s/%s",
+					parsed.Type, parsed.From, parsed.Hash, tt.typeName, from.Hex(), tx.Hash().Hex())
+			}
+			if parsed.To != tx.To().Hex() || *parsed.Nonce != tx.Nonce() || !parsed.Pending {
+				t.Errorf("解析结果与交易不一致: %+v", parsed)
+			}
+
+			dynamic := tx.Type() == types.DynamicFeeTxType || tx.Type() == types.BlobTxType
+			if dynamic != (parsed.MaxFeePerGas != nil && parsed.MaxPriorityFeePerGas != nil) {
+				t.Errorf("MaxFeePerGas/MaxPriorityFeePerGas = %v/%v", parsed.MaxFeePerGas, parsed.MaxPriorityFeePerGas)
+			}
+			if blob := tx.Type() == types.BlobTxType; blob != (parsed.MaxFeePerBlobGas != nil && len(parsed.BlobHashes) == 1) {
+				t.Errorf("MaxFeePerBlobGas/BlobHashes = %v/%v", parsed.MaxFeePerBlobGas, parsed.BlobHashes)
+			}
+			if len(parsed.AccessList) != len(tx.AccessList()) {
+				t.Errorf("AccessList = %v, want %v", parsed.AccessList, tx.AccessList())
+			}
+
+			receipt := &types.Receipt{
+				Type:              tx.Type(),
+				Status:            types.ReceiptStatusSuccessful,
+				TxHash:            tx.Hash(),
+				GasUsed:           21000,
+				CumulativeGasUsed: 21000,
+				EffectiveGasPrice: big.NewInt(12),
+				BlockNumber:       big.NewInt(9),
+			}
+			parsedReceipt := ParseReceipt(receipt, tx)
+			if parsedReceipt.Type != tt.typeName || parsedReceipt.From != from.Hex() || parsedReceipt.To != tx.To().Hex() {
+				t.Errorf("收据类型/发送方/接收方 = %s/%s/%s", parsedReceipt.Type, parsedReceipt.From, parsedReceipt.To)
+			}
+			if parsedReceipt.BlockNumber != 9 || parsedReceipt.Logs == nil {
+				t.Errorf("收据解析结果 = %+v", parsedReceipt)
+			}
+		})
+	}
+}
+
+func TestParseTransactionContractCreation(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	tx, err := types.SignNewTx(key, types.HomesteadSigner{}, &types.LegacyTx{Nonce: 3, Gas: 100000, GasPrice: big.NewInt(1), Data: []byte{0x60}})
+	if err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	parsed := ParseTransaction(tx, false)
+	if want := crypto.CreateAddress(from, 3).Hex(); parsed.ContractAddress != want || parsed.To != "" {
+		t.Errorf("ContractAddress/To = %s/%s, want %s/空", parsed.ContractAddress, parsed.To, want)
+	}
+}
+
+func TestParseReceiptWithoutTransaction(t *testing.T) {
+	receipt := &types.Receipt{
+		Type:            types.BlobTxType,
+		Status:          types.ReceiptStatusFailed,
+		ContractAddress: common.HexToAddress("0x01"),
+		BlobGasUsed:     131072,
+		BlobGasPrice:    big.NewInt(3),
+		Logs:            []*types.Log{{Address: common.HexToAddress("0x02"), Topics: []common.Hash{{0x03}}}},
+	}
+
+	parsed := ParseReceipt(receipt, nil)
+	if parsed.From != "" || parsed.To != "" {
+		t.Errorf("没有交易时发送方/接收方 = %s/%s, want 空", parsed.From, parsed.To)
+	}
+	if parsed.Type != TxTypeBlob || parsed.Status != 0 || parsed.BlobGasUsed != 131072 || parsed.BlobGasPrice.Int64() != 3 {
+		t.Errorf("收据解析结果 = %+v", parsed)
+	}
+	if parsed.BlockNumber != 0 || parsed.ContractAddress != common.HexToAddress("0x01").Hex() || len(parsed.Logs) != 1 {
+		t.Errorf("收据解析结果 = %+v", parsed)
+	}
+}
+
+func TestEffectiveGasPrice(t *testing.T) {
+	legacy := types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(100)})
+	dynamic := types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(60)})
+	blob := types.NewTx(&types.BlobTx{GasTipCap: uint256.NewInt(5), GasFeeCap: uint256.NewInt(60)})
+
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		baseFee *big.Int
+		want    int64
+	}{
+		{"伦敦升级前的legacy交易", legacy, nil, 100},
+		{"伦敦升级后的legacy交易", legacy, big.NewInt(40), 100},
+		{"伦敦升级前的EIP-1559交易", dynamic, nil, 60},
+		{"baseFee加小费", dynamic, big.NewInt(40), 42},
+		{"费用上限截断小费", dynamic, big.NewInt(59), 60},
+		{"费用上限低于baseFee", dynamic, big.NewInt(70), 60},
+		{"blob交易", blob, big.NewInt(10), 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := effectiveGasPrice(tt.tx, tt.baseFee); got.Int64() != tt.want {
+				t.Errorf("effectiveGasPrice() = %s, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+const (
+	// DefaultPollInterval 未配置时追上链头后的轮询间隔
+	DefaultPollInterval = 12 * time.Second
+	// DefaultBlocksPerBatch 未配置时每次批量读取的区块数
+	DefaultBlocksPerBatch = 20
+)
+
+// Source 索引器读取链上数据的来源，*eth.Client 实现了该接口
+type Source interface {
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	GetBlocksRange(ctx context.Context, from, to uint64) ([]*types.Block, error)
+	GetBlockReceipts(ctx context.Context, block *types.Block) ([]*types.Receipt, error)
+}
+
+// Options 索引器参数
+type Options struct {
+	// StartBlock 没有检查点时开始索引的区块号
+	StartBlock uint64
+	// Confirmations 只索引至少有该数量确认的区块，避免写入可能被重组的区块
+	Confirmations uint64
+	// PollInterval 追上链头后等待新区块的轮询间隔
+	PollInterval time.Duration
+	// BlocksPerBatch 每次批量读取的区块数
+	BlocksPerBatch uint64
+}
+
+// Indexer 区块索引器
+// 从检查点（或起始高度）开始批量读取区块及其收据和日志，按区块顺序写入存储，追上链头后按轮询间隔等待新区块
+type Indexer struct {
+	source  Source
+	store   Storage
+	options Options
+}
+
+// New 创建区块索引器
+func New(source Source, store Storage, options Options) *Indexer {
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	if options.BlocksPerBatch == 0 {
+		options.BlocksPerBatch = DefaultBlocksPerBatch
+	}
+
+	return &Indexer{source: source, store: store, options: options}
+}
+
+// Run 持续索引直到ctx被取消
+func (ix *Indexer) Run(ctx context.Context) error {
+	for {
+		indexed, err := ix.Sync(ctx)
+		if err != nil {
+			log.Printf("⚠️  索引失败，稍后重试: %v", err)
+		} else if indexed > 0 {
+			log.Printf("📦 已索引 %d 个区块", indexed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ix.options.PollInterval):
+		}
+	}
+}
+
+// Sync 从检查点索引到当前已确认的链头，返回本次索引的区块数
+func (ix *Indexer) Sync(ctx context.Context) (int, error) {
+	next, err := ix.nextBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	latest, err := ix.source.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if latest < ix.options.Confirmations {
+		return 0, nil
+	}
+	target := latest - ix.options.Confirmations
+
+	indexed := 0
+	for from := next; from <= target; from += ix.options.BlocksPerBatch {
+		if err := ctx.Err(); err != nil {
+			return indexed, err
+		}
+
+		to := min(from+ix.options.BlocksPerBatch-1, target)
+		blocks, err := ix.source.GetBlocksRange(ctx, from, to)
+		if err != nil {
+			return indexed, err
+		}
+		for _, block := range blocks {
+			if err := ix.indexBlock(ctx, block); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+	}
+	return indexed, nil
+}
+
+// IndexBlock 读取单个区块及其全部收据并写入存储，区块不存在时返回 eth.ErrNotFound
+func (ix *Indexer) IndexBlock(ctx context.Context, number uint64) error {
+	blocks, err := ix.source.GetBlocksRange(ctx, number, number)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("区块 %d: %w", number, eth.ErrNotFound)
+	}
+	return ix.indexBlock(ctx, blocks[0])
+}
+
+// indexBlock 读取区块的全部收据，与区块一起写入存储
+func (ix *Indexer) indexBlock(ctx context.Context, block *types.Block) error {
+	receipts, err := ix.source.GetBlockReceipts(ctx, block)
+	if err != nil {
+		return err
+	}
+
+	if err := ix.store.SaveBlock(ctx, NewBlockData(block, receipts)); err != nil {
+		return fmt.Errorf("保存区块 %d 失败: %w", block.NumberU64(), err)
+	}
+	return nil
+}
+
+// nextBlock 返回下一个待索引的区块号：检查点之后的区块，没有检查点时为起始高度
+func (ix *Indexer) nextBlock(ctx context.Context) (uint64, error) {
+	checkpoint, ok, err := ix.store.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || checkpoint < ix.options.StartBlock {
+		return ix.options.StartBlock, nil
+	}
+	return checkpoint + 1, nil
+}
@@ -0,0 +1,209 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"go-eth-backend/internal/pkg/eth"
+)
+
+// fakeSource 内存中的链，每个区块包含一笔带一条日志的转账
+type fakeSource struct {
+	blocks   []*types.Block
+	receipts map[common.Hash]*types.Receipt
+}
+
+// newFakeSource 生成包含创世块在内共n个区块的链
+func newFakeSource(t *testing.T, n int) *fakeSource {
+	t.Helper()
+
+	source := &fakeSource{receipts: make(map[common.Hash]*types.Receipt)}
+	source.extend(t, n)
+	return source
+}
+
+// extend 在链尾追加n个区块
+func (s *fakeSource) extend(t *testing.T, n int) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	chainID := big.NewInt(1337)
+	signer := types.LatestSignerForChainID(chainID)
+	to := common.HexToAddress("0x742d35Cc6634C0532925a3b8Ffb8a2B15a3F2F20")
+
+	for i := 0; i < n; i++ {
+		number := uint64(len(s.blocks))
+		header := &types.Header{
+			Number:   new(big.Int).SetUint64(number),
+			Time:     1700000000 + number*12,
+			GasLimit: 30000000,
+			GasUsed:  21000,
+			BaseFee:  big.NewInt(1e9),
+		}
+		if number > 0 {
+			header.ParentHash = s.blocks[number-1].Hash()
+		}
+
+		tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     number,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: big.NewInt(2e9),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(int64(number)),
+		})
+		receipt := &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			GasUsed:           21000,
+			TxHash:            tx.Hash(),
+			EffectiveGasPrice: big.NewInt(1e9 + 1),
+			Logs: []*types.Log{{
+				Address: to,
+				Topics:  []common.Hash{crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))},
+				Data:    []byte{byte(number)},
+				TxHash:  tx.Hash(),
+			}},
+		}
+
+		block := types.NewBlock(header, &types.Body{Transactions: types.Transactions{tx}}, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+		receipt.BlockHash = block.Hash()
+		receipt.BlockNumber = block.Number()
+
+		s.blocks = append(s.blocks, block)
+		s.receipts[tx.Hash()] = receipt
+	}
+}
+
+func (s *fakeSource) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	return uint64(len(s.blocks) - 1), nil
+}
+
+func (s *fakeSource) GetBlocksRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	if to >= uint64(len(s.blocks)) {
+		return nil, fmt.Errorf("区块 %d 不存在", to)
+	}
+	return s.blocks[from : to+1], nil
+}
+
+func (s *fakeSource) GetBlockReceipts(ctx context.Context, block *types.Block) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		receipt, ok := s.receipts[tx.Hash()]
+		if !ok {
+			return nil, fmt.Errorf("收据 %s 不存在", tx.Hash().Hex())
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+func TestIndexerSync(t *testing.T) {
+	ctx := context.Background()
+	source := newFakeSource(t, 6) // 区块 0..5
+	store := NewMemoryStorage()
+
+	ix := New(source, store, Options{StartBlock: 1, Confirmations: 2, BlocksPerBatch: 2})
+	indexed, err := ix.Sync(ctx)
+	if err != nil {
+		t.Fatalf("索引失败: %v", err)
+	}
+	// 链头为5，需要2个确认，只索引 1..3
+	if indexed != 3 {
+		t.Fatalf("索引区块数 = %d, want 3", indexed)
+	}
+	if checkpoint, ok, _ := store.Checkpoint(ctx); !ok || checkpoint != 3 {
+		t.Fatalf("检查点 = %d (%v), want 3", checkpoint, ok)
+	}
+	if store.Block(0) != nil || store.Block(4) != nil {
+		t.Fatal("索引了范围之外的区块")
+	}
+
+	data := store.Block(2)
+	if data == nil {
+		t.Fatal("区块2未被索引")
+	}
+	block := source.blocks[2]
+	if data.Block.Hash != block.Hash().Hex() || data.Block.ParentHash != source.blocks[1].Hash().Hex() {
+		t.Errorf("区块哈希不匹配: %+v", data.Block)
+	}
+	if len(data.Transactions) != 1 || len(data.Receipts) != 1 || len(data.Logs) != 1 {
+		t.Fatalf("交易/收据/日志数量 = %d/%d/%d, want 1/1/1", len(data.Transactions), len(data.Receipts), len(data.Logs))
+	}
+	tx := data.Transactions[0]
+	if tx.Hash != block.Transactions()[0].Hash().Hex() || tx.From == "" || tx.Value.Int64() != 2 {
+		t.Errorf("交易记录不正确: %+v", tx)
+	}
+	if data.Logs[0].TxHash != tx.Hash || len(data.Logs[0].Topics) != 1 {
+		t.Errorf("日志记录不正确: %+v", data.Logs[0])
+	}
+}
+
+func TestIndexerResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	source := newFakeSource(t, 4) // 区块 0..3
+	store := NewMemoryStorage()
+
+	if _, err := New(source, store, Options{}).Sync(ctx); err != nil {
+		t.Fatalf("索引失败: %v", err)
+	}
+
+	// 模拟重启：新的索引器使用同一存储，只应索引新增的区块
+	source.extend(t, 3)
+	indexed, err := New(source, store, Options{}).Sync(ctx)
+	if err != nil {
+		t.Fatalf("恢复索引失败: %v", err)
+	}
+	if indexed != 3 {
+		t.Fatalf("重启后索引区块数 = %d, want 3", indexed)
+	}
+	if checkpoint, _, _ := store.Checkpoint(ctx); checkpoint != 6 {
+		t.Fatalf("检查点 = %d, want 6", checkpoint)
+	}
+	for number := uint64(0); number <= 6; number++ {
+		if store.Block(number) == nil {
+			t.Errorf("区块 %d 未被索引", number)
+		}
+	}
+}
+
+// emptySource 对任意范围都返回空结果，模拟节点对尚不存在的区块返回null
+type emptySource struct {
+	*fakeSource
+}
+
+func (emptySource) GetBlocksRange(ctx context.Context, from, to uint64) ([]*types.Block, error) {
+	return nil, nil
+}
+
+func TestIndexBlockNotFound(t *testing.T) {
+	ctx := context.Background()
+	source := newFakeSource(t, 2)
+	store := NewMemoryStorage()
+
+	if err := New(source, store, Options{}).IndexBlock(ctx, 1); err != nil {
+		t.Fatalf("索引区块1失败: %v", err)
+	}
+	if store.Block(1) == nil {
+		t.Fatal("区块1未被索引")
+	}
+
+	err := New(emptySource{source}, store, Options{}).IndexBlock(ctx, 5)
+	if !errors.Is(err, eth.ErrNotFound) {
+		t.Fatalf("err = %v, want eth.ErrNotFound", err)
+	}
+	if store.Block(5) != nil {
+		t.Error("不存在的区块不应写入存储")
+	}
+}
@@ -0,0 +1,51 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStorage 进程内存储，用于测试和不需要持久化的场景
+type MemoryStorage struct {
+	mu         sync.RWMutex
+	blocks     map[uint64]*BlockData
+	checkpoint uint64
+	hasBlocks  bool
+}
+
+// NewMemoryStorage 创建空的内存存储
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{blocks: make(map[uint64]*BlockData)}
+}
+
+// SaveBlock 写入区块数据并推进检查点，同一高度重复写入时覆盖旧数据
+func (s *MemoryStorage) SaveBlock(ctx context.Context, data *BlockData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blocks[data.Block.Number] = data
+	s.checkpoint = data.Block.Number
+	s.hasBlocks = true
+	return nil
+}
+
+// Checkpoint 返回最近一次写入的区块号
+func (s *MemoryStorage) Checkpoint(ctx context.Context) (uint64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.checkpoint, s.hasBlocks, nil
+}
+
+// Block 返回指定高度的索引数据，未索引时返回nil
+func (s *MemoryStorage) Block(number uint64) *BlockData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.blocks[number]
+}
+
+// Close 内存存储无需释放资源
+func (s *MemoryStorage) Close() error {
+	return nil
+}
@@ -0,0 +1,257 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/lib/pq"
+)
+
+// postgresSchema 索引表结构，启动时自动创建
+// 所有表按 chain_id 区分网络，多个网络可以共用一个数据库；
+// 交易、收据和日志通过外键级联到区块，重新写入某一高度时删除区块即可清理其旧数据
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS blocks (
+	chain_id    BIGINT NOT NULL,
+	number      BIGINT NOT NULL,
+	hash        TEXT NOT NULL,
+	parent_hash TEXT NOT NULL,
+	timestamp   BIGINT NOT NULL,
+	miner       TEXT NOT NULL,
+	gas_limit   BIGINT NOT NULL,
+	gas_used    BIGINT NOT NULL,
+	base_fee    NUMERIC(78, 0),
+	tx_count    INTEGER NOT NULL,
+	PRIMARY KEY (chain_id, number),
+	UNIQUE (chain_id, hash)
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+	chain_id     BIGINT NOT NULL,
+	hash         TEXT NOT NULL,
+	block_number BIGINT NOT NULL,
+	tx_index     INTEGER NOT NULL,
+	type         SMALLINT NOT NULL,
+	from_address TEXT NOT NULL,
+	to_address   TEXT,
+	value        NUMERIC(78, 0) NOT NULL,
+	nonce        BIGINT NOT NULL,
+	gas          BIGINT NOT NULL,
+	gas_price    NUMERIC(78, 0),
+	gas_tip_cap  NUMERIC(78, 0),
+	gas_fee_cap  NUMERIC(78, 0),
+	input        BYTEA,
+	PRIMARY KEY (chain_id, hash),
+	FOREIGN KEY (chain_id, block_number) REFERENCES blocks (chain_id, number) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS transactions_block_number_idx ON transactions (chain_id, block_number);
+CREATE INDEX IF NOT EXISTS transactions_from_address_idx ON transactions (from_address);
+CREATE INDEX IF NOT EXISTS transactions_to_address_idx ON transactions (to_address);
+
+CREATE TABLE IF NOT EXISTS receipts (
+	chain_id            BIGINT NOT NULL,
+	tx_hash             TEXT NOT NULL,
+	block_number        BIGINT NOT NULL,
+	status              BIGINT NOT NULL,
+	gas_used            BIGINT NOT NULL,
+	cumulative_gas_used BIGINT NOT NULL,
+	effective_gas_price NUMERIC(78, 0),
+	contract_address    TEXT,
+	PRIMARY KEY (chain_id, tx_hash),
+	FOREIGN KEY (chain_id, tx_hash) REFERENCES transactions (chain_id, hash) ON DELETE CASCADE,
+	FOREIGN KEY (chain_id, block_number) REFERENCES blocks (chain_id, number) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS logs (
+	chain_id     BIGINT NOT NULL,
+	block_number BIGINT NOT NULL,
+	log_index    INTEGER NOT NULL,
+	tx_hash      TEXT NOT NULL,
+	tx_index     INTEGER NOT NULL,
+	address      TEXT NOT NULL,
+	topics       TEXT[] NOT NULL,
+	data         BYTEA,
+	PRIMARY KEY (chain_id, block_number, log_index),
+	FOREIGN KEY (chain_id, block_number) REFERENCES blocks (chain_id, number) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS logs_address_idx ON logs (address);
+CREATE INDEX IF NOT EXISTS logs_topic0_idx ON logs ((topics[1]));
+
+CREATE TABLE IF NOT EXISTS indexer_checkpoint (
+	chain_id     BIGINT PRIMARY KEY,
+	block_number BIGINT NOT NULL
+);
+`
+
+// legacySchemaQuery 检查是否存在没有 chain_id 列的旧版索引表
+const legacySchemaQuery = `
+SELECT EXISTS (
+	SELECT 1 FROM information_schema.tables
+	WHERE table_schema = current_schema() AND table_name = 'blocks'
+) AND NOT EXISTS (
+	SELECT 1 FROM information_schema.columns
+	WHERE table_schema = current_schema() AND table_name = 'blocks' AND column_name = 'chain_id'
+)`
+
+// PostgresStorage 基于PostgreSQL的索引存储，只读写chainID对应网络的数据
+type PostgresStorage struct {
+	db      *sql.DB
+	chainID int64
+}
+
+// NewPostgresStorage 连接PostgreSQL并创建索引表，chainID 为被索引网络的链ID
+func NewPostgresStorage(ctx context.Context, dsn string, chainID int64) (*PostgresStorage, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库失败: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	s, err := newPostgresStorage(ctx, db, chainID)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// newPostgresStorage 在已打开的数据库上创建索引表
+func newPostgresStorage(ctx context.Context, db *sql.DB, chainID int64) (*PostgresStorage, error) {
+	var legacy bool
+	if err := db.QueryRowContext(ctx, legacySchemaQuery).Scan(&legacy); err != nil {
+		return nil, fmt.Errorf("检查索引表结构失败: %w", err)
+	}
+	if legacy {
+		return nil, errors.New("数据库中存在没有 chain_id 列的旧版索引表，请删除旧表或使用新的数据库后重新索引")
+	}
+
+	if _, err := db.ExecContext(ctx, postgresSchema); err != nil {
+		return nil, fmt.Errorf("创建索引表失败: %w", err)
+	}
+	return &PostgresStorage{db: db, chainID: chainID}, nil
+}
+
+// SaveBlock 在一个数据库事务中写入区块数据并推进检查点
+func (s *PostgresStorage) SaveBlock(ctx context.Context, data *BlockData) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("开启数据库事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveBlock(ctx, tx, s.chainID, data); err != nil {
+		return fmt.Errorf("写入区块 %d 失败: %w", data.Block.Number, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO indexer_checkpoint (chain_id, block_number) VALUES ($1, $2)
+		ON CONFLICT (chain_id) DO UPDATE SET block_number = EXCLUDED.block_number`,
+		s.chainID, int64(data.Block.Number)); err != nil {
+		return fmt.Errorf("更新检查点失败: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交数据库事务失败: %w", err)
+	}
+	return nil
+}
+
+// saveBlock 写入区块、交易、收据和日志，同一高度或同一哈希已有数据时先删除
+// 链重组后交易可能被重新打包到其他高度，写入前按哈希删除旧的交易行（级联删除其收据）
+func saveBlock(ctx context.Context, tx *sql.Tx, chainID int64, data *BlockData) error {
+	block := data.Block
+	if _, err := tx.ExecContext(ctx, `DELETE FROM blocks WHERE chain_id = $1 AND (number = $2 OR hash = $3)`,
+		chainID, int64(block.Number), block.Hash); err != nil {
+		return err
+	}
+
+	if len(data.Transactions) > 0 {
+		hashes := make([]string, len(data.Transactions))
+		for i, t := range data.Transactions {
+			hashes[i] = t.Hash
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM transactions WHERE chain_id = $1 AND hash = ANY($2)`,
+			chainID, pq.Array(hashes)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO blocks (chain_id, number, hash, parent_hash, timestamp, miner, gas_limit, gas_used, base_fee, tx_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		chainID, int64(block.Number), block.Hash, block.ParentHash, int64(block.Timestamp), block.Miner,
+		int64(block.GasLimit), int64(block.GasUsed), numeric(block.BaseFee), block.TxCount); err != nil {
+		return err
+	}
+
+	for _, t := range data.Transactions {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO transactions (chain_id, hash, block_number, tx_index, type, from_address, to_address, value, nonce, gas, gas_price, gas_tip_cap, gas_fee_cap, input)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+			chainID, t.Hash, int64(t.BlockNumber), t.Index, int16(t.Type), t.From, nullString(t.To), numeric(t.Value),
+			int64(t.Nonce), int64(t.Gas), numeric(t.GasPrice), numeric(t.GasTipCap), numeric(t.GasFeeCap), t.Input); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range data.Receipts {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO receipts (chain_id, tx_hash, block_number, status, gas_used, cumulative_gas_used, effective_gas_price, contract_address)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			chainID, r.TxHash, int64(r.BlockNumber), int64(r.Status), int64(r.GasUsed), int64(r.CumulativeGasUsed),
+			numeric(r.EffectiveGasPrice), nullString(r.ContractAddress)); err != nil {
+			return err
+		}
+	}
+
+	for _, l := range data.Logs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO logs (chain_id, block_number, log_index, tx_hash, tx_index, address, topics, data)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			chainID, int64(l.BlockNumber), int64(l.LogIndex), l.TxHash, int64(l.TxIndex), l.Address, pq.Array(l.Topics), l.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Checkpoint 返回本网络最近一次写入的区块号
+func (s *PostgresStorage) Checkpoint(ctx context.Context) (uint64, bool, error) {
+	var number int64
+	err := s.db.QueryRowContext(ctx, `SELECT block_number FROM indexer_checkpoint WHERE chain_id = $1`, s.chainID).Scan(&number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("读取检查点失败: %w", err)
+	}
+	return uint64(number), true, nil
+}
+
+// Close 关闭数据库连接
+func (s *PostgresStorage) Close() error {
+	return s.db.Close()
+}
+
+// numeric 将大整数转换为NUMERIC参数，nil写入NULL
+func numeric(v *big.Int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return v.String()
+}
+
+// nullString 空字符串写入NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
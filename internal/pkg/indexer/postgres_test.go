@@ -0,0 +1,288 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// postgresDSNEnv 设置后对真实的PostgreSQL运行存储测试
+const postgresDSNEnv = "INDEXER_TEST_POSTGRES_DSN"
+
+// statement 记录的一条SQL语句及其参数
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+// recordingConnector 记录所有语句的 database/sql 驱动，用于在没有数据库时检查存储发出的SQL
+type recordingConnector struct {
+	mu         sync.Mutex
+	statements []statement
+	legacy     bool   // 检查旧版表结构时返回的结果
+	checkpoint *int64 // 查询检查点时返回的区块号，nil表示没有检查点
+}
+
+func (c *recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return &recordingConn{c}, nil
+}
+func (c *recordingConnector) Driver() driver.Driver { return nil }
+
+func (c *recordingConnector) record(query string, args []driver.NamedValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.statements = append(c.statements, statement{query: strings.Join(strings.Fields(query), " "), args: values})
+}
+
+// reset 清空已记录的语句
+func (c *recordingConnector) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statements = nil
+}
+
+type recordingConn struct {
+	c *recordingConnector
+}
+
+func (conn *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("不支持预编译语句")
+}
+func (conn *recordingConn) Close() error { return nil }
+func (conn *recordingConn) Begin() (driver.Tx, error) {
+	conn.c.record("BEGIN", nil)
+	return conn, nil
+}
+func (conn *recordingConn) Commit() error {
+	conn.c.record("COMMIT", nil)
+	return nil
+}
+func (conn *recordingConn) Rollback() error {
+	conn.c.record("ROLLBACK", nil)
+	return nil
+}
+
+func (conn *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	conn.c.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (conn *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	conn.c.record(query, args)
+	switch {
+	case strings.Contains(query, "information_schema"):
+		return &recordingRows{column: "legacy", values: []driver.Value{conn.c.legacy}}, nil
+	case strings.Contains(query, "indexer_checkpoint") && conn.c.checkpoint != nil:
+		return &recordingRows{column: "block_number", values: []driver.Value{*conn.c.checkpoint}}, nil
+	default:
+		return &recordingRows{column: "block_number"}, nil
+	}
+}
+
+// recordingRows 单列结果集
+type recordingRows struct {
+	column string
+	values []driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return []string{r.column} }
+func (r *recordingRows) Close() error      { return nil }
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+// reorgedBlockData 返回区块2和重组后的区块1'，区块2中的交易被重新打包进区块1'
+func reorgedBlockData(t *testing.T) (block2, replacement *BlockData) {
+	t.Helper()
+
+	source := newFakeSource(t, 3)
+	blocks := make([]*BlockData, len(source.blocks))
+	for i, block := range source.blocks {
+		receipts, err := source.GetBlockReceipts(context.Background(), block)
+		if err != nil {
+			t.Fatalf("获取收据失败: %v", err)
+		}
+		blocks[i] = NewBlockData(block, receipts)
+	}
+
+	block2 = blocks[2]
+	replacement = &BlockData{
+		Block:        blocks[1].Block,
+		Transactions: append([]TransactionRecord{}, block2.Transactions...),
+		Receipts:     append([]ReceiptRecord{}, block2.Receipts...),
+		Logs:         append([]LogRecord{}, block2.Logs...),
+	}
+	replacement.Block.Hash = "0x" + strings.Repeat("ab", 32)
+	for i := range replacement.Transactions {
+		replacement.Transactions[i].BlockNumber = 1
+	}
+	for i := range replacement.Receipts {
+		replacement.Receipts[i].BlockNumber = 1
+	}
+	for i := range replacement.Logs {
+		replacement.Logs[i].BlockNumber = 1
+	}
+	return block2, replacement
+}
+
+func TestPostgresStorageStatements(t *testing.T) {
+	ctx := context.Background()
+	const chainID = 11155111
+
+	connector := &recordingConnector{}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	store, err := newPostgresStorage(ctx, db, chainID)
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	_, replacement := reorgedBlockData(t)
+	connector.reset()
+	if err := store.SaveBlock(ctx, replacement); err != nil {
+		t.Fatalf("写入区块失败: %v", err)
+	}
+
+	want := []string{
+		"BEGIN",
+		"DELETE FROM blocks",
+		"DELETE FROM transactions",
+		"INSERT INTO blocks",
+		"INSERT INTO transactions",
+		"INSERT INTO receipts",
+		"INSERT INTO logs",
+		"INSERT INTO indexer_checkpoint",
+		"COMMIT",
+	}
+	if len(connector.statements) != len(want) {
+		t.Fatalf("语句数量 = %d, want %d: %v", len(connector.statements), len(want), connector.statements)
+	}
+	for i, prefix := range want {
+		stmt := connector.statements[i]
+		if !strings.HasPrefix(stmt.query, prefix) {
+			t.Errorf("第 %d 条语句 = %q, want %s...", i, stmt.query, prefix)
+		}
+		if prefix == "BEGIN" || prefix == "COMMIT" {
+			continue
+		}
+		// 每条读写语句都以链ID作为第一个参数
+		if len(stmt.args) == 0 || stmt.args[0] != int64(chainID) {
+			t.Errorf("%s 的第一个参数应为链ID %d, got %v", prefix, chainID, stmt.args)
+		}
+	}
+
+	// 重新打包的交易在写入前按哈希删除旧行
+	deleteTxs := connector.statements[2]
+	if !strings.Contains(deleteTxs.query, "hash = ANY($2)") || !strings.Contains(deleteTxs.args[1].(string), replacement.Transactions[0].Hash) {
+		t.Errorf("删除交易语句 = %q %v, want 按哈希 %s 删除", deleteTxs.query, deleteTxs.args, replacement.Transactions[0].Hash)
+	}
+
+	checkpoint := int64(7)
+	connector.checkpoint = &checkpoint
+	connector.reset()
+	number, ok, err := store.Checkpoint(ctx)
+	if err != nil || !ok || number != 7 {
+		t.Fatalf("Checkpoint() = %d, %v, %v, want 7", number, ok, err)
+	}
+	if args := connector.statements[0].args; len(args) != 1 || args[0] != int64(chainID) {
+		t.Errorf("检查点查询参数 = %v, want [%d]", args, chainID)
+	}
+}
+
+func TestPostgresStorageRejectsLegacySchema(t *testing.T) {
+	connector := &recordingConnector{legacy: true}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if _, err := newPostgresStorage(context.Background(), db, 1); err == nil || !strings.Contains(err.Error(), "chain_id") {
+		t.Fatalf("err = %v, want 旧版表结构错误", err)
+	}
+	for _, stmt := range connector.statements {
+		if strings.Contains(stmt.query, "CREATE TABLE") {
+			t.Error("检测到旧版表结构时不应创建索引表")
+		}
+	}
+}
+
+func TestPostgresStorage(t *testing.T) {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("未设置 %s，跳过PostgreSQL测试", postgresDSNEnv)
+	}
+
+	ctx := context.Background()
+	// 以当前时间生成链ID，避免与其他测试或已有数据冲突
+	chainID := time.Now().UnixNano() % 1e12
+	otherChainID := chainID + 1
+
+	store, err := NewPostgresStorage(ctx, dsn, chainID)
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	other, err := NewPostgresStorage(ctx, dsn, otherChainID)
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	t.Cleanup(func() { other.Close() })
+	// 清理在关闭连接之前执行
+	t.Cleanup(func() {
+		store.db.Exec(`DELETE FROM blocks WHERE chain_id = ANY(ARRAY[$1, $2]::BIGINT[])`, chainID, otherChainID)
+		store.db.Exec(`DELETE FROM indexer_checkpoint WHERE chain_id = ANY(ARRAY[$1, $2]::BIGINT[])`, chainID, otherChainID)
+	})
+
+	block2, replacement := reorgedBlockData(t)
+
+	// 两个网络写入相同高度和相同哈希的数据互不冲突
+	for _, s := range []*PostgresStorage{store, other} {
+		if err := s.SaveBlock(ctx, block2); err != nil {
+			t.Fatalf("写入区块2失败: %v", err)
+		}
+	}
+
+	// 重组后交易被打包进区块1'，区块2尚未被覆盖
+	if err := store.SaveBlock(ctx, replacement); err != nil {
+		t.Fatalf("写入重组后的区块1失败: %v", err)
+	}
+
+	txHash := replacement.Transactions[0].Hash
+	var blockNumber int64
+	if err := store.db.QueryRowContext(ctx, `SELECT block_number FROM transactions WHERE chain_id = $1 AND hash = $2`,
+		chainID, txHash).Scan(&blockNumber); err != nil {
+		t.Fatalf("查询交易失败: %v", err)
+	}
+	if blockNumber != 1 {
+		t.Errorf("交易所在区块 = %d, want 1", blockNumber)
+	}
+	if err := store.db.QueryRowContext(ctx, `SELECT block_number FROM transactions WHERE chain_id = $1 AND hash = $2`,
+		otherChainID, txHash).Scan(&blockNumber); err != nil || blockNumber != 2 {
+		t.Errorf("另一网络的交易所在区块 = %d (%v), want 2", blockNumber, err)
+	}
+
+	for _, tt := range []struct {
+		store *PostgresStorage
+		want  uint64
+	}{{store, 1}, {other, 2}} {
+		number, ok, err := tt.store.Checkpoint(ctx)
+		if err != nil || !ok || number != tt.want {
+			t.Errorf("链 %d 的检查点 = %d, %v, %v, want %d", tt.store.chainID, number, ok, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Storage 索引数据存储
+// SaveBlock 必须原子地写入区块数据并推进检查点，保证重启后从检查点继续时不会遗漏或重复
+type Storage interface {
+	// SaveBlock 写入区块及其交易、收据和日志，并将检查点更新为该区块号
+	SaveBlock(ctx context.Context, data *BlockData) error
+	// Checkpoint 返回最近一次写入的区块号，尚未写入任何区块时ok为false
+	Checkpoint(ctx context.Context) (number uint64, ok bool, err error)
+	// Close 释放存储占用的资源
+	Close() error
+}
+
+// BlockData 一个区块的全部索引数据
+type BlockData struct {
+	Block        BlockRecord
+	Transactions []TransactionRecord
+	Receipts     []ReceiptRecord
+	Logs         []LogRecord
+}
+
+// BlockRecord 区块记录
+type BlockRecord struct {
+	Number     uint64
+	Hash       string
+	ParentHash string
+	Timestamp  uint64
+	Miner      string
+	GasLimit   uint64
+	GasUsed    uint64
+	BaseFee    *big.Int // London之前的区块为nil
+	TxCount    int
+}
+
+// TransactionRecord 交易记录
+type TransactionRecord struct {
+	Hash        string
+	BlockNumber uint64
+	Index       int
+	Type        uint8
+	From        string
+	To          string // 合约创建交易为空
+	Value       *big.Int
+	Nonce       uint64
+	Gas         uint64
+	GasPrice    *big.Int
+	GasTipCap   *big.Int
+	GasFeeCap   *big.Int
+	Input       []byte
+}
+
+// ReceiptRecord 交易收据记录
+type ReceiptRecord struct {
+	TxHash            string
+	BlockNumber       uint64
+	Status            uint64
+	GasUsed           uint64
+	CumulativeGasUsed uint64
+	EffectiveGasPrice *big.Int
+	ContractAddress   string // 非合约创建交易为空
+}
+
+// LogRecord 事件日志记录
+type LogRecord struct {
+	BlockNumber uint64
+	TxHash      string
+	TxIndex     uint
+	LogIndex    uint
+	Address     string
+	Topics      []string
+	Data        []byte
+}
+
+// NewBlockData 将原生区块和按交易顺序排列的收据转换为索引数据
+func NewBlockData(block *types.Block, receipts []*types.Receipt) *BlockData {
+	data := &BlockData{
+		Block: BlockRecord{
+			Number:     block.NumberU64(),
+			Hash:       block.Hash().Hex(),
+			ParentHash: block.ParentHash().Hex(),
+			Timestamp:  block.Time(),
+			Miner:      block.Coinbase().Hex(),
+			GasLimit:   block.GasLimit(),
+			GasUsed:    block.GasUsed(),
+			BaseFee:    block.BaseFee(),
+			TxCount:    len(block.Transactions()),
+		},
+	}
+
+	for i, tx := range block.Transactions() {
+		record := TransactionRecord{
+			Hash:        tx.Hash().Hex(),
+			BlockNumber: block.NumberU64(),
+			Index:       i,
+			Type:        tx.Type(),
+			Value:       tx.Value(),
+			Nonce:       tx.Nonce(),
+			Gas:         tx.Gas(),
+			GasPrice:    tx.GasPrice(),
+			Input:       tx.Data(),
+		}
+		if tx.Type() != types.LegacyTxType && tx.Type() != types.AccessListTxType {
+			record.GasTipCap = tx.GasTipCap()
+			record.GasFeeCap = tx.GasFeeCap()
+		}
+		if tx.To() != nil {
+			record.To = tx.To().Hex()
+		}
+		if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+			record.From = from.Hex()
+		}
+		data.Transactions = append(data.Transactions, record)
+	}
+
+	for _, receipt := range receipts {
+		record := ReceiptRecord{
+			TxHash:            receipt.TxHash.Hex(),
+			BlockNumber:       block.NumberU64(),
+			Status:            receipt.Status,
+			GasUsed:           receipt.GasUsed,
+			CumulativeGasUsed: receipt.CumulativeGasUsed,
+			EffectiveGasPrice: receipt.EffectiveGasPrice,
+		}
+		if receipt.ContractAddress != (common.Address{}) {
+			record.ContractAddress = receipt.ContractAddress.Hex()
+		}
+		data.Receipts = append(data.Receipts, record)
+
+		for _, log := range receipt.Logs {
+			topics := make([]string, len(log.Topics))
+			for i, topic := range log.Topics {
+				topics[i] = topic.Hex()
+			}
+			data.Logs = append(data.Logs, LogRecord{
+				BlockNumber: block.NumberU64(),
+				TxHash:      log.TxHash.Hex(),
+				TxIndex:     log.TxIndex,
+				LogIndex:    log.Index,
+				Address:     log.Address.Hex(),
+				Topics:      topics,
+				Data:        log.Data,
+			})
+		}
+	}
+
+	return data
+}